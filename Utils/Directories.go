@@ -58,6 +58,7 @@ func NewDirectory() {
 	}
 }
 
+// FindGitRepos returns every folder under root with a .git folder in it, leaving out node_modules and vendor
 func FindGitRepos(root string) []string {
 	var repos []string
 	filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
@@ -68,6 +69,9 @@ func FindGitRepos(root string) []string {
 			repos = append(repos, filepath.Dir(path))
 			return filepath.SkipDir // stop traversing this subdir
 		}
+		if d.IsDir() && path != root && IsSkippedDirectory(d.Name()) {
+			return filepath.SkipDir
+		}
 		return nil
 	})
 	return repos
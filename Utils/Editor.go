@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// EditorMarker is shown at the end of the text given to the editor, everything from it down is thrown away
+const EditorMarker = "<!-- Everything below this line is ignored -->"
+
+// OpenEditor writes the text to a temporary markdown file, opens it in $VISUAL / $EDITOR (vi if neither are set)
+// and returns what was saved. Help is shown below EditorMarker so it never ends up in the issue
+func OpenEditor(text, help string) (string, error) {
+	file, err := os.CreateTemp("", "thoth-*.md")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(file.Name())
+
+	contents := text
+	if help != "" {
+		contents += "\n\n" + EditorMarker + "\n" + help + "\n"
+	}
+
+	if _, err := file.WriteString(contents); err != nil {
+		file.Close()
+		return "", err
+	}
+	file.Close()
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	// The editor can come with arguments e.g. "code --wait"
+	editorParts := strings.Fields(editor)
+	cmd := exec.Command(editorParts[0], append(editorParts[1:], file.Name())...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+
+	saved, err := os.ReadFile(file.Name())
+	if err != nil {
+		return "", err
+	}
+
+	edited, _, _ := strings.Cut(string(saved), EditorMarker)
+	edited = strings.TrimSpace(edited)
+
+	if edited == "" {
+		return "", errors.New("nothing was written in the editor so stopping")
+	}
+
+	return edited, nil
+}
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+
+	aphrodite "github.com/jonathon-chew/Aphrodite"
+)
+
+var (
+	markdownHeading  = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	markdownBullet   = regexp.MustCompile(`^(\s*)[-*+]\s+(\[[ xX]\]\s+)?(.*)$`)
+	markdownNumbered = regexp.MustCompile(`^(\s*)(\d+)[.)]\s+(.*)$`)
+	markdownRule     = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+	markdownBold     = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	markdownCode     = regexp.MustCompile("`([^`]+)`")
+	markdownLink     = regexp.MustCompile(`!?\[([^\]]*)\]\(([^)\s]+)[^)]*\)`)
+	markdownComment  = regexp.MustCompile(`<!--.*?-->`)
+)
+
+// RenderMarkdown turns an issue body into something readable in the terminal
+// Headings, lists, quotes, rules and code blocks are handled line by line, with bold, code and links inside a line
+func RenderMarkdown(markdown string) string {
+	var b strings.Builder
+	var inCodeBlock bool
+
+	// Hidden comments are for machines (and Thoth's own markers) not people
+	markdown = markdownComment.ReplaceAllString(markdown, "")
+
+	for _, line := range strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+
+		if inCodeBlock {
+			code, _ := aphrodite.ReturnColour("Yellow", line)
+			b.WriteString("    " + code + "\n")
+			continue
+		}
+
+		if match := markdownHeading.FindStringSubmatch(trimmed); match != nil {
+			heading := match[2]
+			if len(match[1]) == 1 {
+				heading = strings.ToUpper(heading)
+			}
+			bold, _ := aphrodite.ReturnBold("Cyan", heading)
+			b.WriteString(bold + "\n")
+			continue
+		}
+
+		if markdownRule.MatchString(line) {
+			b.WriteString(strings.Repeat("─", 40) + "\n")
+			continue
+		}
+
+		if match := markdownBullet.FindStringSubmatch(line); match != nil {
+			bullet := "•"
+			switch strings.TrimSpace(match[2]) {
+			case "[ ]":
+				bullet = "☐"
+			case "[x]", "[X]":
+				bullet = "☑"
+			}
+			b.WriteString(match[1] + "  " + bullet + " " + renderInlineMarkdown(match[3]) + "\n")
+			continue
+		}
+
+		if match := markdownNumbered.FindStringSubmatch(line); match != nil {
+			b.WriteString(match[1] + "  " + match[2] + ". " + renderInlineMarkdown(match[3]) + "\n")
+			continue
+		}
+
+		if quote, found := strings.CutPrefix(trimmed, ">"); found {
+			bar, _ := aphrodite.ReturnColour("Purple", "│")
+			b.WriteString(bar + " " + renderInlineMarkdown(strings.TrimSpace(quote)) + "\n")
+			continue
+		}
+
+		b.WriteString(renderInlineMarkdown(line) + "\n")
+	}
+
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+func renderInlineMarkdown(line string) string {
+	line = markdownLink.ReplaceAllString(line, "$1 ($2)")
+
+	line = markdownCode.ReplaceAllStringFunc(line, func(code string) string {
+		coloured, _ := aphrodite.ReturnColour("Yellow", strings.Trim(code, "`"))
+		return coloured
+	})
+
+	line = markdownBold.ReplaceAllStringFunc(line, func(bold string) string {
+		coloured, _ := aphrodite.ReturnBold("White", strings.Trim(bold, "*_"))
+		return coloured
+	})
+
+	return line
+}
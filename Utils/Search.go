@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"bytes"
+	"path/filepath"
+	"slices"
+)
+
+// Folders that are never worth searching
+var skippedDirectories = []string{".git", "node_modules", "vendor", filepath.Base(TemporaryDirectory)}
+
+// IsSkippedDirectory is true for the folders tree walks leave out
+func IsSkippedDirectory(name string) bool {
+	return slices.Contains(skippedDirectories, name)
+}
+
+// IsBinary treats anything with a NUL byte in the first 8KB as binary, the same check git uses
+func IsBinary(contents []byte) bool {
+	return bytes.IndexByte(contents[:min(len(contents), 8000)], 0) != -1
+}
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	aphrodite "github.com/jonathon-chew/Aphrodite"
+)
+
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws the values as one line of block characters, from the lowest value to the highest
+func Sparkline(values []int) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	low, high := slices.Min(values), slices.Max(values)
+
+	var line strings.Builder
+	for _, value := range values {
+		level := len(sparks) - 1
+		if high > low {
+			level = (value - low) * (len(sparks) - 1) / (high - low)
+		}
+		line.WriteRune(sparks[level])
+	}
+	return line.String()
+}
+
+// RenderTrend prints a bar for each value next to its label, the biggest value fills width
+// Bars are red when the value went up from the one before and green when it went down, unless option is non-ansii
+func RenderTrend(labels []string, values []int, width int, option string) {
+	if len(values) == 0 {
+		return
+	}
+
+	high := max(slices.Max(values), 1)
+	labelWidth := 0
+	for _, label := range labels {
+		labelWidth = max(labelWidth, visibleWidth(label))
+	}
+
+	for index, value := range values {
+		bar := strings.Repeat("█", value*width/high)
+		if value > 0 && bar == "" {
+			bar = "▏"
+		}
+
+		if option != "non-ansii" && index > 0 && value != values[index-1] {
+			colour := "Green"
+			if value > values[index-1] {
+				colour = "Red"
+			}
+			if coloured, err := aphrodite.ReturnColour(colour, bar); err == nil {
+				bar = coloured
+			}
+		}
+
+		fmt.Printf("%-*s  %s %d\n", labelWidth, labels[index], bar, value)
+	}
+
+	fmt.Printf("\n%s  %d to %d\n", Sparkline(values), values[0], values[len(values)-1])
+}
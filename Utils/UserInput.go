@@ -2,8 +2,10 @@ package utils
 
 import (
 	"bufio"
+	"fmt"
 	"log"
 	"os"
+	"strings"
 )
 
 func GetUserInput(message []byte) (string, error) {
@@ -25,3 +27,27 @@ func GetUserInput(message []byte) (string, error) {
 
 	return userInput, nil
 }
+
+// One reader for every prompt so input typed ahead isn't lost in a discarded buffer
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// Prompt prints the message to stdout and returns the line typed back without the new line
+func Prompt(message string) (string, error) {
+	fmt.Print(message)
+
+	line, err := stdinReader.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// IsTerminal is true when the file is an interactive terminal rather than a pipe or a file
+func IsTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	aphrodite "github.com/jonathon-chew/Aphrodite"
+	"github.com/jonathon-chew/Thoth/config"
+	"github.com/jonathon-chew/Thoth/git"
+	"github.com/jonathon-chew/Thoth/report"
+	"github.com/jonathon-chew/Thoth/scanner"
+)
+
+// ErrPolicy is returned by thoth check-todos when a TODO breaks the policy, so a CI job can tell it apart from thoth failing
+var ErrPolicy = errors.New("TODOs break the policy")
+
+// thoth check-todos [--keyword TODO,FIXME] [--offline]
+// Fails when a TODO in the tree breaks the check policy in .thoth.json, --offline skips the rule that needs the issue tracker
+func checkTodosCommand(arguments []string) error {
+	settings, err := config.Load(".")
+	if err != nil {
+		return err
+	}
+	policy := settings.Check
+
+	if keywords := flagValues(arguments, "--keyword"); len(keywords) > 0 {
+		policy.Keywords = keywords
+	}
+
+	findings, scanErr := scanner.New(scanner.Options{Keywords: policy.Keywords}).ScanTree(".")
+	if scanErr != nil {
+		return scanErr
+	}
+
+	var states report.IssueStates
+	if policy.ClosedIssues && slices.Contains(arguments, "--offline") {
+		aphrodite.PrintWarning("Offline: TODOs numbered with closed issues weren't checked\n")
+	} else if policy.ClosedIssues {
+		if states, err = issueStates(findings); err != nil {
+			return err
+		}
+	}
+
+	violations := report.CheckPolicy(findings, policy, states, time.Now())
+	if len(violations) == 0 {
+		aphrodite.PrintInfo(fmt.Sprintf("All %d TODOs pass the policy\n", len(findings)))
+		return nil
+	}
+
+	aphrodite.PrintError(fmt.Sprintf("%d of %d TODOs break the policy\n", len(violations), len(findings)))
+	if err := report.WriteViolations(os.Stdout, violations); err != nil {
+		return err
+	}
+
+	return fmt.Errorf("%w: %d found", ErrPolicy, len(violations))
+}
+
+// Looks up every issue a TODO is numbered with, issueWorkers at a time. Issues that don't exist are left out
+func issueStates(findings []scanner.Finding) (report.IssueStates, error) {
+	var numbers []int
+	for _, finding := range findings {
+		if finding.IssueNumber != 0 && !slices.Contains(numbers, finding.IssueNumber) {
+			numbers = append(numbers, finding.IssueNumber)
+		}
+	}
+
+	states := report.IssueStates{}
+	if len(numbers) == 0 {
+		return states, nil
+	}
+
+	credentials, err := git.GenericGitRequest()
+	if err != nil {
+		return nil, err
+	}
+
+	var lock sync.Mutex
+	var errs []error
+
+	slots := make(chan struct{}, issueWorkers)
+	var workers sync.WaitGroup
+	for _, number := range numbers {
+		workers.Add(1)
+		slots <- struct{}{}
+
+		go func(number int) {
+			defer func() { <-slots; workers.Done() }()
+
+			issue, err := git.GetIssue(credentials, number)
+
+			lock.Lock()
+			defer lock.Unlock()
+
+			var apiError *git.APIError
+			switch {
+			case errors.As(err, &apiError) && (apiError.StatusCode == http.StatusNotFound || apiError.StatusCode == http.StatusGone):
+			case err != nil:
+				errs = append(errs, fmt.Errorf("unable to get issue #%d: %w", number, err))
+			default:
+				states[number] = issue.State
+			}
+		}(number)
+	}
+	workers.Wait()
+
+	return states, errors.Join(errs...)
+}
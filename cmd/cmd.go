@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"slices"
 	"strings"
+	"time"
 
 	aphrodite "github.com/jonathon-chew/Aphrodite"
 	utils "github.com/jonathon-chew/Thoth/Utils"
@@ -15,8 +17,6 @@ import (
 func CLI(CommandLineArguments []string) error {
 	// aphrodite.PrintColour("Cyan", "I have found additional command line arguments, switching to CLI mode\n")
 
-	var NoIssues error = errors.New("no GitHub issues found")
-
 	for index, command := range CommandLineArguments {
 		switch command {
 		case "--commit-calendar", "--cc", "-cc":
@@ -43,8 +43,20 @@ func CLI(CommandLineArguments []string) error {
 			return nil
 
 		case "--get", "-get", "-g":
-			returned, err := git.ListGithubIssues(true)
-			if err != nil && errors.Is(err, NoIssues) {
+			var returned []git.GithubIssueResponse
+			var err error
+
+			if slices.Contains(CommandLineArguments, "--offline") {
+				var savedAt time.Time
+				returned, savedAt, err = git.ListGithubIssuesOffline()
+				if err == nil || errors.Is(err, git.ErrNoIssues) {
+					aphrodite.PrintWarning(fmt.Sprintf("Offline: showing issues cached at %s\n", savedAt.Local().Format("2006-01-02 15:04")))
+				}
+			} else {
+				returned, err = git.ListGithubIssues(true)
+			}
+
+			if errors.Is(err, git.ErrNoIssues) {
 				aphrodite.PrintWarning("no GitHub issues found")
 				return nil
 			}
@@ -55,36 +67,40 @@ func CLI(CommandLineArguments []string) error {
 
 			var closedFlag, openFlag bool = false, true
 			// Check for extra flags
-			if len(os.Args) > 2 {
-				for _, extraCommand := range os.Args[2:] {
-					switch extraCommand {
-					case "--closed", "-closed", "-c":
-						closedFlag = true
-					case "--all", "-all", "-a":
-						openFlag = false
-					}
+			for _, extraCommand := range CommandLineArguments[index+1:] {
+				switch extraCommand {
+				case "--closed", "-closed", "-c":
+					closedFlag = true
+				case "--all", "-all", "-a":
+					openFlag = false
 				}
 			}
 
-			for index, issue := range returned {
-				if closedFlag && issue.State == "closed" {
-					fmt.Printf("%d The issue title is:\n%s\nThe body is: %s\nThe status is: %s\n\n", index+1, strings.TrimSpace(issue.Title), issue.Body, aphrodite.ReturnWarning(issue.State))
-					fmt.Printf("______________\n")
-					continue
-				}
+			format, ErrReadingFormat := flagValue(CommandLineArguments[index+1:], "--format", "-format", "-f")
+			if ErrReadingFormat != nil {
+				return ErrReadingFormat
+			}
 
-				if openFlag && issue.State == "open" {
-					fmt.Printf("%d The issue title is:\n%s\nThe body is: %s\nThe status is: %s\n\n", index+1, strings.TrimSpace(issue.Title), issue.Body, aphrodite.ReturnInfo(issue.State))
-					fmt.Printf("______________\n")
-					continue
-				}
+			columns, ErrReadingColumns := flagValue(CommandLineArguments[index+1:], "--columns", "-columns")
+			if ErrReadingColumns != nil {
+				return ErrReadingColumns
+			}
 
-				if !closedFlag && !openFlag {
-					fmt.Printf("%d The issue title is:\n%s\nThe body is: %s\nThe status is: %s\n\n", index+1, strings.TrimSpace(issue.Title), issue.Body, issue.State)
-					fmt.Printf("______________\n")
+			// Filter once, keeping where each issue was in the list for the numbered output
+			var matchingIssues []git.GithubIssueResponse
+			var positions []int
+			for index, issue := range returned {
+				if (closedFlag && issue.State == "closed") || (openFlag && issue.State == "open") || (!closedFlag && !openFlag) {
+					matchingIssues = append(matchingIssues, issue)
+					positions = append(positions, index+1)
 				}
 			}
 
+			if format != "" {
+				return printIssues(os.Stdout, matchingIssues, format, columns)
+			}
+
+			printIssueDetails(matchingIssues, positions, closedFlag, openFlag)
 			return nil
 
 		case "--set", "-set", "-s":
@@ -101,14 +117,48 @@ func CLI(CommandLineArguments []string) error {
 				return errors.New("could not find a body flag proceeding the set command")
 			}
 
-			makeError := git.MakeGithubIssue(IssueTitle, IssueBody)
-			if makeError != nil {
-				fmt.Println(makeError)
-				return makeError
+			credentials, err := git.GenericGitRequest()
+			if err != nil {
+				return err
+			}
+
+			filer, err := NewIssueFiler(credentials, ".")
+			if err != nil {
+				return err
+			}
+
+			issue, created, err := filer.File(git.Github_Issue{Title: strings.TrimSpace(IssueTitle), Body: IssueBody})
+			if err != nil {
+				return err
+			}
+
+			if created {
+				fmt.Printf(git.CreatedIssue+"%d %s\n", issue.Number, issue.Html_url)
+			} else {
+				fmt.Printf("Not made, it is already #%d %s\n", issue.Number, issue.Html_url)
 			}
 
 			return nil
 
+		case "issues", "issue":
+			return issuesCommand(CommandLineArguments[index+1:])
+
+		case "todos", "todo":
+			return todosCommand(CommandLineArguments[index+1:])
+
+		case "check-todos":
+			return checkTodosCommand(CommandLineArguments[index+1:])
+
+		case "hooks", "hook":
+			return hooksCommand(CommandLineArguments[index+1:])
+
+		case "auth":
+			if len(CommandLineArguments) <= index+1 || CommandLineArguments[index+1] != "status" {
+				return errors.New("auth expects a sub command, the only one available is status")
+			}
+
+			return authStatus()
+
 		case "--version", "-version", "-v":
 			fmt.Printf("v0.7.1\n")
 
@@ -118,10 +168,40 @@ func CLI(CommandLineArguments []string) error {
 			aphrodite.PrintColour("Green", "You can run with no arguments to check all the files in the current directory for any undocumented todos and upload them to github\n\n")
 
 			aphrodite.PrintBold("Cyan", "Get issues\n")
-			aphrodite.PrintColour("Green", "You can pass in a get flag which will List the github issues, this can be supplimented with --open and --closed to filter to show only issues with those flags\n\n")
+			aphrodite.PrintColour("Green", "You can pass in a get flag which will List the github issues, this can be supplimented with --open and --closed to filter to show only issues with those flags. Add --offline to show the issues cached by the last online run without using the network. Use --format table|json|csv|markdown for output other tools can read, and --columns to pick from number, title, state, labels, assignees, created, updated and url\n\n")
+
+			aphrodite.PrintBold("Cyan", "List issues\n")
+			aphrodite.PrintColour("Green", "issues list takes a query such as: label:bug assignee:me author:someone state:closed created:>2026-01-01 updated:2026-01-01..2026-02-01 \"parser\" - any word without a key is searched for in the text. Sort with --sort created|updated|comments and --order asc|desc, cap the results with --limit and pick the output with --format and --columns\n\n")
+
+			aphrodite.PrintBold("Cyan", "View an issue\n")
+			aphrodite.PrintColour("Green", "issues view [number] shows the issue with its comments and history, and every TODO, FIXME, HACK or XXX numbered (#number) in the code\n\n")
+
+			aphrodite.PrintBold("Cyan", "Create issues\n")
+			aphrodite.PrintColour("Green", "issues create with --title, --body or --body-file [path] (- reads stdin), --template [name], --label and --assignee. Templates come from .github/ISSUE_TEMPLATE or .gitlab/issue_templates and fill in the title, labels, assignees and body. Without --template you're asked to choose one, and without a body $EDITOR is opened where the first line becomes the title\n\n")
+
+			aphrodite.PrintBold("Cyan", "Change issues\n")
+			aphrodite.PrintColour("Green", "issues comment [number] --body text, issues edit [number] with --title, --body, --add-label, --remove-label, --add-assignee and --remove-assignee, issues close [number] --reason completed|not_planned and issues reopen [number]. Close and reopen take --comment to explain why. When no body is given $EDITOR is opened to write one\n\n")
 
 			aphrodite.PrintBold("Cyan", "Set issues\n")
-			aphrodite.PrintColour("Green", "If you pass in the set flag, please pass in the title flag and body flag (in that order) to make a new issue with the relevent Title and Body\n\n")
+			aphrodite.PrintColour("Green", "If you pass in the set flag, please pass in the title flag and body flag (in that order) to make a new issue with the relevent Title and Body. If an open or recently closed issue has a similar title you're offered that issue instead, see duplicates in .thoth.json\n\n")
+
+			aphrodite.PrintBold("Cyan", "Scan\n")
+			aphrodite.PrintColour("Green", "Running thoth with no arguments (or thoth scan) makes issues for new TODOs. scan --since [ref] only looks at the lines added or changed since the ref branched off (e.g. origin/main in a pull request job) and scan --staged only at the staged lines (e.g. in a pre-commit hook). Untracked files aren't part of either. scan --review goes through each new TODO with the lines around it to accept, skip, edit (title, body and labels), link to an existing issue or ignore it (thoth:ignore is written in front of the keyword), then does the whole batch once it is confirmed. scan --workspace ~/src scans every git repository under the folder, a few at once, each with its own remote, credentials and .thoth.json, and ends with a summary per repository. A TODO(p1, due:2026-12-01) comment gives the new issue a priority label (metadata.priority_label in .thoth.json) and a due date, a milestone due that day on GitHub. thoth:ignore on a line, or thoth:ignore-next-line on the line above, leaves a TODO out, and a .thothignore at the root (written like a .gitignore) leaves out whole files and folders\n\n")
+
+			aphrodite.PrintBold("Cyan", "TODO report\n")
+			aphrodite.PrintColour("Green", "todos lists every TODO, FIXME, HACK and XXX (or just the --keyword ones) numbered or not, without using the network or changing any file. --group file|keyword|owner|age (owner is the name in TODO(name) and age comes from git blame) and --format table|json|sarif|codeclimate|markdown (sarif is for GitHub code scanning, codeclimate for GitLab code quality). --age adds the commit, author and date that added each line, --sort age puts the oldest first and --older-than 90d (or 12w, 1y) only shows the older ones. todos history counts them at the last commit of each --every week|day|commit (optionally --since 1y) and draws the trend, or --format sparkline|csv|json\n\n")
+
+			aphrodite.PrintBold("Cyan", "Check TODOs\n")
+			aphrodite.PrintColour("Green", "check-todos fails (exit code 6) when a TODO has no issue number, is numbered with a closed issue or has a due: or by: date, as in TODO(due:2026-01-31), that has passed, for use as a CI gate. Each rule and the keywords checked are set under check in .thoth.json, and --offline skips looking up the issues\n\n")
+
+			aphrodite.PrintBold("Cyan", "Hooks\n")
+			aphrodite.PrintColour("Green", "hooks install writes pre-commit and pre-push hooks (just one with --pre-commit or --pre-push) into the hooks folder git uses, including core.hooksPath. An existing hook is kept unless --chain (it runs first) or --force (it's saved as .backup) is passed in. Set hooks.mode in .thoth.json to block (the default) to stop commits adding TODOs without a number, or create to make their issues and stage the numbered lines. pre-push always blocks. hooks uninstall removes them\n\n")
+
+			aphrodite.PrintBold("Cyan", "Auth Status\n")
+			aphrodite.PrintColour("Green", "auth status reports which credential source was used (GH_PERSONAL_TOKEN, GITHUB_TOKEN, GH_TOKEN, GL_PERSONAL_TOKEN, GITLAB_TOKEN, the gh / glab config, git credential or .netrc) and the scopes on the token\n\n")
+
+			aphrodite.PrintBold("Cyan", "Exit Codes\n")
+			aphrodite.PrintColour("Green", "0 success, 1 general error, 2 no git remote, 3 no token found, 4 token rejected by the API, 5 any other API error, 6 check-todos found TODOs that break the policy\n\n")
 
 			aphrodite.PrintBold("Cyan", "Version\n")
 			aphrodite.PrintColour("Green", "Version Number can be passed in with the version flag\n\n")
@@ -189,3 +269,29 @@ func CLI(CommandLineArguments []string) error {
 
 	return nil
 }
+
+func authStatus() error {
+	credentials, err := git.GenericGitRequest()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Host: %s\n", credentials.Host)
+	fmt.Printf("Repository: %s/%s\n", credentials.Owner, credentials.Repo)
+	fmt.Printf("Token source: %s\n", aphrodite.ReturnInfo(credentials.Source))
+
+	scopes, ErrGettingScopes := git.TokenScopes(credentials)
+	if ErrGettingScopes != nil {
+		aphrodite.PrintWarning(fmt.Sprintf("Unable to check the token scopes: %s\n", ErrGettingScopes))
+		return nil
+	}
+
+	if len(scopes) == 0 {
+		fmt.Printf("Scopes: none reported (fine-grained tokens don't list scopes)\n")
+		return nil
+	}
+
+	fmt.Printf("Scopes: %s\n", strings.Join(scopes, ", "))
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	aphrodite "github.com/jonathon-chew/Aphrodite"
+	utils "github.com/jonathon-chew/Thoth/Utils"
+	"github.com/jonathon-chew/Thoth/git"
+)
+
+// thoth issues create [--title text] [--body text | --body-file path|-] [--template name] [--label a,b] [--assignee x]
+// Anything not given is filled in from the template, then $EDITOR when there's a terminal to open it in
+func issuesCreate(arguments []string) error {
+	title, err := flagValue(arguments, "--title", "-title", "-t")
+	if err != nil {
+		return err
+	}
+
+	body, err := bodyArgument(arguments)
+	if err != nil {
+		return err
+	}
+
+	templateName, err := flagValue(arguments, "--template", "-template", "-T")
+	if err != nil {
+		return err
+	}
+
+	templates, err := git.FindIssueTemplates(".")
+	if err != nil {
+		return err
+	}
+
+	template, err := chooseIssueTemplate(templates, templateName, title == "" && body == "")
+	if err != nil {
+		return err
+	}
+
+	issue := git.Github_Issue{
+		Title:     title,
+		Body:      body,
+		Label:     flagValues(arguments, "--label"),
+		Assignees: flagValues(arguments, "--assignee"),
+	}
+
+	if template != nil {
+		issue.Label = append(append([]string{}, template.Labels...), issue.Label...)
+		issue.Assignees = append(append([]string{}, template.Assignees...), issue.Assignees...)
+		if issue.Body == "" {
+			issue.Body = template.Body
+		}
+	}
+
+	// Only open the editor when the body wasn't passed in and someone is there to write it
+	if body == "" && utils.IsTerminal(os.Stdin) {
+		if issue.Title != "" {
+			issue.Body, err = utils.OpenEditor(issue.Body, fmt.Sprintf("Write the body of the issue %q above this line.", issue.Title))
+			if err != nil {
+				return err
+			}
+		} else {
+			prefill := issue.Body
+			if template != nil {
+				prefill = template.Title + "\n\n" + issue.Body
+			}
+
+			edited, err := utils.OpenEditor(prefill, "The first line is the title, everything after it is the body.")
+			if err != nil {
+				return err
+			}
+
+			firstLine, rest, _ := strings.Cut(edited, "\n")
+			issue.Title, issue.Body = strings.TrimSpace(firstLine), strings.TrimSpace(rest)
+		}
+	} else if issue.Title == "" && template != nil {
+		issue.Title = template.Title
+	}
+
+	if strings.TrimSpace(issue.Title) == "" {
+		return errors.New("an issue needs a title, pass one with --title")
+	}
+
+	credentials, err := git.GenericGitRequest()
+	if err != nil {
+		return err
+	}
+
+	created, err := git.CreateIssue(credentials, issue)
+	if err != nil {
+		return err
+	}
+
+	aphrodite.PrintInfo(fmt.Sprintf("Created #%d %s\n", created.Number, created.Html_url))
+	return nil
+}
+
+// The template named with --template, or one picked from a list when nothing was passed in and there's a terminal to ask on
+func chooseIssueTemplate(templates []git.IssueTemplate, name string, ask bool) (*git.IssueTemplate, error) {
+	if name != "" {
+		for index := range templates {
+			if templates[index].Matches(name) {
+				return &templates[index], nil
+			}
+		}
+
+		var names []string
+		for _, template := range templates {
+			names = append(names, template.Name)
+		}
+		if len(names) == 0 {
+			return nil, fmt.Errorf("there is no template called %s, this repository has no issue templates", name)
+		}
+		return nil, fmt.Errorf("there is no template called %s, choose from: %s", name, strings.Join(names, ", "))
+	}
+
+	if !ask || len(templates) == 0 || !utils.IsTerminal(os.Stdin) {
+		return nil, nil
+	}
+
+	fmt.Println("0: Blank issue")
+	for index, template := range templates {
+		if template.About != "" {
+			fmt.Printf("%d: %s - %s\n", index+1, template.Name, template.About)
+		} else {
+			fmt.Printf("%d: %s\n", index+1, template.Name)
+		}
+	}
+
+	for {
+		answer, err := utils.Prompt("Which template? ")
+		if err != nil {
+			return nil, err
+		}
+
+		choice, err := strconv.Atoi(strings.TrimSpace(answer))
+		if err != nil || choice < 0 || choice > len(templates) {
+			aphrodite.PrintWarning(fmt.Sprintf("Please choose a number from 0 to %d\n", len(templates)))
+			continue
+		}
+
+		if choice == 0 {
+			return nil, nil
+		}
+		return &templates[choice-1], nil
+	}
+}
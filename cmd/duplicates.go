@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	aphrodite "github.com/jonathon-chew/Aphrodite"
+	utils "github.com/jonathon-chew/Thoth/Utils"
+	"github.com/jonathon-chew/Thoth/config"
+	"github.com/jonathon-chew/Thoth/git"
+)
+
+// IssueFiler makes issues for a repository, checking each one against the existing issues first so TODOs get linked rather than repeated
+type IssueFiler struct {
+	Credentials git.Credentials
+	Settings    config.Config
+	candidates  []git.GithubIssueResponse
+	lock        sync.Mutex // File and Sync are called from several goroutines by the scan
+
+	// How Sync changes an issue, so tests can watch the changes instead of making them
+	editIssue      func(credentials git.Credentials, number int, changes git.IssueChanges) (git.GithubIssueResponse, error)
+	commentOnIssue func(credentials git.Credentials, number int, body string) (git.Github_Comment, error)
+}
+
+// NewIssueFiler reads the settings from the repository in directory and fetches the issues to compare against
+func NewIssueFiler(credentials git.Credentials, directory string) (*IssueFiler, error) {
+	settings, err := config.Load(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := git.DuplicateCandidates(credentials, time.Duration(settings.Duplicates.ClosedWithinDays)*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	return &IssueFiler{
+		Credentials:    credentials,
+		Settings:       settings,
+		candidates:     candidates,
+		editIssue:      git.EditIssue,
+		commentOnIssue: git.CommentOnIssue,
+	}, nil
+}
+
+// File returns an existing issue when the title matches one closely enough and the settings (or the person asked) say to link it,
+// otherwise it makes a new issue. created says which happened
+// When the body carries a fingerprint (see git.Fingerprint) an issue with a matching one is linked straight away
+func (filer *IssueFiler) File(issue git.Github_Issue) (git.GithubIssueResponse, bool, error) {
+	existing, found, err := filer.findExisting(issue)
+	if err != nil || found {
+		return existing, false, err
+	}
+
+	created, err := filer.create(issue)
+	return created, err == nil, err
+}
+
+// Makes the issue without looking for an existing one, for when that has already been decided
+func (filer *IssueFiler) create(issue git.Github_Issue) (git.GithubIssueResponse, error) {
+	created, err := git.CreateIssue(filer.Credentials, issue)
+	if err != nil {
+		return created, err
+	}
+
+	// A TODO later in the run that looks like this one can be linked to it like any other issue
+	filer.lock.Lock()
+	filer.candidates = append(filer.candidates, created)
+	filer.lock.Unlock()
+
+	return created, nil
+}
+
+// Suggest returns the existing issue the new one would most likely be linked to and why, without asking anything:
+// one with the same fingerprint, or else the one with the most alike title at or above the threshold
+func (filer *IssueFiler) Suggest(issue git.Github_Issue) (git.GithubIssueResponse, string, bool) {
+	filer.lock.Lock()
+	defer filer.lock.Unlock()
+
+	if fingerprint, found := git.ParseFingerprint(issue.Body); found {
+		if existing, found := git.FindByFingerprint(fingerprint, filer.candidates); found {
+			return existing, "it was made for this TODO", true
+		}
+	}
+
+	if matches := git.FindDuplicates(issue.Title, filer.candidates, filer.Settings.Duplicates.Threshold); len(matches) > 0 {
+		return matches[0].Issue, fmt.Sprintf("%.0f%% alike", matches[0].Score*100), true
+	}
+
+	return git.GithubIssueResponse{}, "", false
+}
+
+// known is the issue with the number from the ones fetched to compare against, if it is one of them
+func (filer *IssueFiler) known(number int) (git.GithubIssueResponse, bool) {
+	filer.lock.Lock()
+	defer filer.lock.Unlock()
+
+	for _, candidate := range filer.candidates {
+		if candidate.Number == number {
+			return candidate, true
+		}
+	}
+	return git.GithubIssueResponse{}, false
+}
+
+// Held under the lock for the whole check, so only one question is asked at a time
+func (filer *IssueFiler) findExisting(issue git.Github_Issue) (git.GithubIssueResponse, bool, error) {
+	filer.lock.Lock()
+	defer filer.lock.Unlock()
+
+	if fingerprint, found := git.ParseFingerprint(issue.Body); found {
+		if existing, found := git.FindByFingerprint(fingerprint, filer.candidates); found {
+			aphrodite.PrintInfo(fmt.Sprintf("%q is already #%d %s, linking it back\n", strings.TrimSpace(issue.Title), existing.Number, strings.TrimSpace(existing.Title)))
+			return existing, true, nil
+		}
+	}
+
+	matches := git.FindDuplicates(issue.Title, filer.candidates, filer.Settings.Duplicates.Threshold)
+	if len(matches) == 0 {
+		return git.GithubIssueResponse{}, false, nil
+	}
+
+	return filer.chooseDuplicate(issue.Title, matches)
+}
+
+func (filer *IssueFiler) chooseDuplicate(title string, matches []git.DuplicateMatch) (git.GithubIssueResponse, bool, error) {
+	best := matches[0]
+
+	action := filer.Settings.Duplicates.Action
+	if action == "ask" && !utils.IsTerminal(os.Stdin) {
+		// There's no one to ask so don't risk a duplicate
+		action = "link"
+	}
+
+	switch action {
+	case "link":
+		aphrodite.PrintWarning(fmt.Sprintf("%q looks like #%d %s (%.0f%% alike) so linking to it\n", strings.TrimSpace(title), best.Issue.Number, strings.TrimSpace(best.Issue.Title), best.Score*100))
+		return best.Issue, true, nil
+	case "create":
+		aphrodite.PrintWarning(fmt.Sprintf("%q looks like #%d %s (%.0f%% alike), making it anyway\n", strings.TrimSpace(title), best.Issue.Number, strings.TrimSpace(best.Issue.Title), best.Score*100))
+		return git.GithubIssueResponse{}, false, nil
+	}
+
+	fmt.Printf("%q looks like an issue that already exists:\n", strings.TrimSpace(title))
+	for _, match := range matches[:min(len(matches), 5)] {
+		fmt.Printf("  #%d [%s] %s (%.0f%% alike)\n", match.Issue.Number, match.Issue.State, strings.TrimSpace(match.Issue.Title), match.Score*100)
+	}
+
+	for {
+		answer, err := utils.Prompt(fmt.Sprintf("Link to #%d, another number from the list, or n to make a new issue? [Y/n/number] ", best.Issue.Number))
+		if err != nil {
+			return git.GithubIssueResponse{}, false, err
+		}
+
+		answer = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(answer), "#"))
+		switch answer {
+		case "", "y", "yes":
+			return best.Issue, true, nil
+		case "n", "no":
+			return git.GithubIssueResponse{}, false, nil
+		}
+
+		if number, err := strconv.Atoi(answer); err == nil {
+			for _, match := range matches {
+				if match.Issue.Number == number {
+					return match.Issue, true, nil
+				}
+			}
+		}
+
+		aphrodite.PrintWarning("Please answer y, n or one of the issue numbers listed\n")
+	}
+}
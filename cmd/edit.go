@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	aphrodite "github.com/jonathon-chew/Aphrodite"
+	utils "github.com/jonathon-chew/Thoth/Utils"
+	"github.com/jonathon-chew/Thoth/git"
+)
+
+// thoth issues comment <number> [--body text | --body-file path]
+func issuesComment(arguments []string) error {
+	number, err := issueNumberArgument(arguments)
+	if err != nil {
+		return err
+	}
+
+	body, err := bodyArgument(arguments)
+	if err != nil {
+		return err
+	}
+
+	if body == "" {
+		body, err = utils.OpenEditor("", fmt.Sprintf("Write your comment on issue #%d above this line.", number))
+		if err != nil {
+			return err
+		}
+	}
+
+	credentials, err := git.GenericGitRequest()
+	if err != nil {
+		return err
+	}
+
+	comment, err := git.CommentOnIssue(credentials, number, body)
+	if err != nil {
+		return err
+	}
+
+	aphrodite.PrintInfo(fmt.Sprintf("Commented on #%d %s\n", number, comment.Html_url))
+	return nil
+}
+
+// thoth issues edit <number> [--title text] [--body text | --body-file path] [--add-label a,b] [--remove-label c] [--add-assignee x] [--remove-assignee y]
+// With no flags the current body is opened in $EDITOR
+func issuesEdit(arguments []string) error {
+	number, err := issueNumberArgument(arguments)
+	if err != nil {
+		return err
+	}
+
+	var changes git.IssueChanges
+	var changed bool
+
+	title, err := flagValue(arguments, "--title", "-title", "-t")
+	if err != nil {
+		return err
+	}
+	if title != "" {
+		changes.Title = &title
+		changed = true
+	}
+
+	body, err := bodyArgument(arguments)
+	if err != nil {
+		return err
+	}
+	if body != "" {
+		changes.Body = &body
+		changed = true
+	}
+
+	for flag, list := range map[string]*[]string{
+		"--add-label":       &changes.AddLabels,
+		"--remove-label":    &changes.RemoveLabels,
+		"--add-assignee":    &changes.AddAssignees,
+		"--remove-assignee": &changes.RemoveAssignees,
+	} {
+		*list = flagValues(arguments, flag)
+		changed = changed || len(*list) > 0
+	}
+
+	credentials, err := git.GenericGitRequest()
+	if err != nil {
+		return err
+	}
+
+	if !changed {
+		issue, err := git.GetIssue(credentials, number)
+		if err != nil {
+			return err
+		}
+
+		body, err = utils.OpenEditor(issue.Body, fmt.Sprintf("Editing the body of #%d %s", number, strings.TrimSpace(issue.Title)))
+		if err != nil {
+			return err
+		}
+		if body == strings.TrimSpace(issue.Body) {
+			aphrodite.PrintWarning("The body wasn't changed so nothing was updated\n")
+			return nil
+		}
+		changes.Body = &body
+	}
+
+	issue, err := git.EditIssue(credentials, number, changes)
+	if err != nil {
+		return err
+	}
+
+	aphrodite.PrintInfo(fmt.Sprintf("Updated #%d %s\n", issue.Number, issue.Html_url))
+	return nil
+}
+
+// thoth issues close <number> [--reason completed|not_planned] [--comment text]
+func issuesClose(arguments []string) error {
+	reason, err := flagValue(arguments, "--reason", "-reason", "-r")
+	if err != nil {
+		return err
+	}
+
+	switch reason {
+	case "", "completed":
+		reason = "completed"
+	case "not_planned", "not-planned":
+		reason = "not_planned"
+	default:
+		return fmt.Errorf("unknown reason %q, use completed or not_planned", reason)
+	}
+
+	return setIssueState(arguments, git.IssueChanges{State: "closed", StateReason: reason})
+}
+
+// thoth issues reopen <number> [--comment text]
+func issuesReopen(arguments []string) error {
+	return setIssueState(arguments, git.IssueChanges{State: "open", StateReason: "reopened"})
+}
+
+func setIssueState(arguments []string, changes git.IssueChanges) error {
+	number, err := issueNumberArgument(arguments)
+	if err != nil {
+		return err
+	}
+
+	comment, err := flagValue(arguments, "--comment", "-comment", "-c")
+	if err != nil {
+		return err
+	}
+
+	credentials, err := git.GenericGitRequest()
+	if err != nil {
+		return err
+	}
+
+	// Comment first so the reason sits above the state change in the timeline
+	if comment != "" {
+		if _, err := git.CommentOnIssue(credentials, number, comment); err != nil {
+			return err
+		}
+	}
+
+	issue, err := git.EditIssue(credentials, number, changes)
+	if err != nil {
+		return err
+	}
+
+	if issue.State != changes.State {
+		return errors.New("the issue state didn't change, check the token can write to issues")
+	}
+
+	aphrodite.PrintInfo(fmt.Sprintf("#%d is now %s\n", issue.Number, issue.State))
+	return nil
+}
+
+// Every value given for the flag, comma separated values are split up so both --add-label a --add-label b and --add-label a,b work
+func flagValues(arguments []string, name string) []string {
+	var values []string
+
+	for index, argument := range arguments {
+		var value string
+		if argument == name && index+1 < len(arguments) {
+			value = arguments[index+1]
+		} else if after, found := strings.CutPrefix(argument, name+"="); found {
+			value = after
+		} else {
+			continue
+		}
+
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				values = append(values, part)
+			}
+		}
+	}
+
+	return values
+}
+
+// The body from --body, or read from the file given to --body-file where - means stdin
+func bodyArgument(arguments []string) (string, error) {
+	body, err := flagValue(arguments, "--body", "-body", "-b")
+	if err != nil || body != "" {
+		return body, err
+	}
+
+	bodyFile, err := flagValue(arguments, "--body-file", "-body-file", "-F")
+	if err != nil || bodyFile == "" {
+		return "", err
+	}
+
+	var contents []byte
+	if bodyFile == "-" {
+		contents, err = io.ReadAll(os.Stdin)
+	} else {
+		contents, err = os.ReadFile(bodyFile)
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to read the body from %s: %w", bodyFile, err)
+	}
+
+	return strings.TrimSpace(string(contents)), nil
+}
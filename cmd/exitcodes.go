@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/jonathon-chew/Thoth/git"
+)
+
+// Exit codes returned by Thoth so scripts can tell failures apart
+const (
+	ExitOK       = 0
+	ExitError    = 1 // Anything not covered below
+	ExitNoRemote = 2 // Not in a repo with a remote origin
+	ExitNoToken  = 3 // No token could be found in the credential chain
+	ExitAuth     = 4 // The API rejected the token (401 / 403)
+	ExitAPI      = 5 // Any other error status from the API
+	ExitPolicy   = 6 // thoth check-todos found TODOs that break the policy
+)
+
+// ExitCode maps an error from CLI (or the default scan) onto one of the exit codes above
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var apiError *git.APIError
+
+	switch {
+	case errors.Is(err, ErrPolicy):
+		return ExitPolicy
+	case errors.Is(err, git.ErrNoRemote):
+		return ExitNoRemote
+	case errors.Is(err, git.ErrNoToken):
+		return ExitNoToken
+	case errors.As(err, &apiError):
+		if apiError.StatusCode == http.StatusUnauthorized || apiError.StatusCode == http.StatusForbidden {
+			return ExitAuth
+		}
+		return ExitAPI
+	}
+
+	return ExitError
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/jonathon-chew/Thoth/git"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, ExitOK},
+		{"anything else", errors.New("something broke"), ExitError},
+		{"no remote", git.ErrNoRemote, ExitNoRemote},
+		{"no token", git.ErrNoToken, ExitNoToken},
+		{"unauthorized", &git.APIError{StatusCode: http.StatusUnauthorized}, ExitAuth},
+		{"forbidden", &git.APIError{StatusCode: http.StatusForbidden}, ExitAuth},
+		{"not found", &git.APIError{StatusCode: http.StatusNotFound}, ExitAPI},
+		{"server error", &git.APIError{StatusCode: http.StatusBadGateway}, ExitAPI},
+		{"policy", ErrPolicy, ExitPolicy},
+		{"wrapped no remote", fmt.Errorf("listing issues: %w", git.ErrNoRemote), ExitNoRemote},
+		{"wrapped no token", fmt.Errorf("auth: %w", git.ErrNoToken), ExitNoToken},
+		{"wrapped unauthorized", fmt.Errorf("creating the issue: %w", &git.APIError{StatusCode: http.StatusUnauthorized}), ExitAuth},
+		{"wrapped api error", fmt.Errorf("creating the issue: %w", &git.APIError{StatusCode: http.StatusUnprocessableEntity}), ExitAPI},
+	}
+
+	for _, test := range tests {
+		if got := ExitCode(test.err); got != test.want {
+			t.Errorf("%s: ExitCode(%v) = %d, want %d", test.name, test.err, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/jonathon-chew/Thoth/git"
+	"github.com/jonathon-chew/Thoth/scanner"
+)
+
+// How many issues are made or synced at once, more than this trips GitHub's secondary rate limit on creating content
+const issueWorkers = 4
+
+// Review is what was decided for a new TODO in thoth scan --review
+type Review struct {
+	Skip  bool              // Leave the TODO without an issue this time
+	Link  int               // Number the TODO with this existing issue rather than making one
+	Issue *git.Github_Issue // The issue to make, as accepted or edited, without checking for duplicates again
+}
+
+// FileFindings syncs the numbered TODOs and makes (or links) an issue for the rest, issueWorkers at a time
+// reviewed holds the decisions from ReviewFindings by the index of the finding, nil when nothing was reviewed
+// It returns the issue number for each finding, 0 where an issue couldn't be made or was skipped, and an error when any
+// issue couldn't be made, so the scan can fail once the rest are numbered
+func FileFindings(filer *IssueFiler, findings []scanner.Finding, reviewed map[int]Review) ([]int, error) {
+	numbers := make([]int, len(findings))
+	failures := make([]error, len(findings))
+
+	var historyLock sync.Mutex
+	histories := map[string][]string{}
+	pathHistory := func(path string) []string {
+		historyLock.Lock()
+		history, found := histories[path]
+		historyLock.Unlock()
+		if found {
+			return history
+		}
+
+		// git runs outside the lock so workers on other files don't wait for it, two on the same file just both ask
+		history = git.PathHistory(path)
+
+		historyLock.Lock()
+		histories[path] = history
+		historyLock.Unlock()
+		return history
+	}
+
+	// Only the first line carrying a number speaks for the issue
+	var jobs []int
+	synced := map[int]bool{}
+	for index, found := range findings {
+		if found.IssueNumber != 0 {
+			numbers[index] = found.IssueNumber
+			if !synced[found.IssueNumber] {
+				synced[found.IssueNumber] = true
+				jobs = append(jobs, index)
+			}
+		}
+	}
+
+	jobs = append(jobs, newTODOs(findings)...)
+
+	slots := make(chan struct{}, issueWorkers)
+	var workers sync.WaitGroup
+	for _, index := range jobs {
+		workers.Add(1)
+		slots <- struct{}{}
+
+		go func(index int) {
+			defer func() { <-slots; workers.Done() }()
+			found := findings[index]
+
+			if found.IssueNumber != 0 {
+				// (#22) TODO: If github issue not in the list of old todos close issue
+				// (#21) TODO: If todo in the list of old todos and no longer open on github, remove line
+
+				// Copy a reworded or moved TODO back to its issue
+				err := filer.Sync(NumberedTODO{Number: found.IssueNumber, Line: found.Source, Path: found.Path, LineNo: found.Line, Paths: pathHistory(found.Path)})
+				if err != nil {
+					fmt.Printf("[ERROR]: Unable to sync #%d from %s line %d: %v\n", found.IssueNumber, found.Path, found.Line, err)
+				}
+				return
+			}
+
+			var issue git.GithubIssueResponse
+			var created bool
+			var err error
+
+			review, wasReviewed := reviewed[index]
+			switch {
+			case wasReviewed && review.Skip:
+				return
+			case wasReviewed && review.Link != 0:
+				numbers[index] = review.Link
+				return
+			case wasReviewed && review.Issue != nil:
+				issue, err = filer.create(*review.Issue)
+				created = err == nil
+			default:
+				// Links to an existing issue instead when the fingerprint matches or one with a similar title is already there
+				issue, created, err = filer.File(newTODOIssue(filer, found, pathHistory(found.Path)))
+			}
+
+			if err != nil {
+				fmt.Printf("[ERROR]: Unable to make an issue for %s line %d: %v\n", found.Path, found.Line, err)
+				failures[index] = err
+				return
+			}
+
+			if created {
+				fmt.Printf(git.CreatedIssue+"%d for %s line %d %s\n", issue.Number, found.Path, found.Line, issue.Html_url)
+			}
+			numbers[index] = issue.Number
+		}(index)
+	}
+	workers.Wait()
+
+	var failed []error
+	for _, err := range failures {
+		if err != nil {
+			failed = append(failed, err)
+		}
+	}
+	if len(failed) > 0 {
+		// The first failure decides the exit code, the rest were printed as they happened
+		return numbers, fmt.Errorf("no issue could be made for %d of the new TODOs: %w", len(failed), failed[0])
+	}
+
+	return numbers, nil
+}
+
+// newTODOs returns the index of each TODO without a number, each gets its own issue
+func newTODOs(findings []scanner.Finding) []int {
+	var indexes []int
+	for index, found := range findings {
+		if found.IssueNumber == 0 {
+			indexes = append(indexes, index)
+		}
+	}
+	return indexes
+}
+
+// The issue made for a new TODO comment, with its line as the title, where it is and its fingerprint in the body, and its priority and due date
+func newTODOIssue(filer *IssueFiler, found scanner.Finding, paths []string) git.Github_Issue {
+	fingerprint := git.NewFingerprint(found.Source, paths)
+
+	issue := git.Github_Issue{
+		Title: strings.TrimSpace(found.Source),
+		Body:  fmt.Sprintf("%s\n\n%s\n", git.TODOReference(found.Path, found.Line), fingerprint.Comment()),
+	}
+	if found.Priority != "" {
+		issue.Label = []string{filer.Settings.Metadata.Label(found.Priority)}
+	}
+	if found.Due != "" {
+		if _, err := scanner.ParseDue(found.Due); err != nil {
+			fmt.Printf("[WARNING]: Ignoring the due date on %s line %d: %v\n", found.Path, found.Line, err)
+		} else {
+			issue.DueDate = found.Due
+		}
+	}
+
+	return issue
+}
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	aphrodite "github.com/jonathon-chew/Aphrodite"
+	"github.com/jonathon-chew/Thoth/git"
+)
+
+// A column that can be picked with --columns, value is what goes into JSON and text is used by the other formats
+type issueColumn struct {
+	name  string
+	value func(issue git.GithubIssueResponse) any
+}
+
+var issueColumns = []issueColumn{
+	{"number", func(issue git.GithubIssueResponse) any { return issue.Number }},
+	{"title", func(issue git.GithubIssueResponse) any { return strings.TrimSpace(issue.Title) }},
+	{"state", func(issue git.GithubIssueResponse) any { return issue.State }},
+	{"labels", func(issue git.GithubIssueResponse) any {
+		labels := []string{}
+		for _, label := range issue.Labels {
+			labels = append(labels, label.Name)
+		}
+		return labels
+	}},
+	{"assignees", func(issue git.GithubIssueResponse) any {
+		assignees := []string{}
+		for _, assignee := range issue.Assignees {
+			assignees = append(assignees, assignee.Login)
+		}
+		return assignees
+	}},
+	{"created", func(issue git.GithubIssueResponse) any { return issue.Created_at }},
+	{"updated", func(issue git.GithubIssueResponse) any { return issue.Updated_at }},
+	{"url", func(issue git.GithubIssueResponse) any { return issue.Html_url }},
+}
+
+var defaultIssueColumns = []string{"number", "title", "state", "labels", "assignees", "updated"}
+
+var issueFormats = []string{"table", "json", "csv", "markdown"}
+
+// Turn the comma separated --columns value into the columns to print, in the order they were asked for
+func selectIssueColumns(requested string) ([]issueColumn, error) {
+	names := defaultIssueColumns
+	if requested != "" {
+		names = strings.Split(requested, ",")
+	}
+
+	var selected []issueColumn
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		found := false
+		for _, column := range issueColumns {
+			if column.name == name {
+				selected = append(selected, column)
+				found = true
+				break
+			}
+		}
+		if !found {
+			var available []string
+			for _, column := range issueColumns {
+				available = append(available, column.name)
+			}
+			return nil, fmt.Errorf("unknown column %q, choose from %s", name, strings.Join(available, ", "))
+		}
+	}
+
+	return selected, nil
+}
+
+func columnText(value any) string {
+	switch typed := value.(type) {
+	case []string:
+		return strings.Join(typed, ", ")
+	default:
+		return fmt.Sprint(typed)
+	}
+}
+
+// The dates come back as RFC 3339, the day is enough when reading a table
+func shortColumnText(column issueColumn, value any) string {
+	text := columnText(value)
+	if (column.name == "created" || column.name == "updated") && len(text) >= 10 {
+		return text[:10]
+	}
+	return text
+}
+
+func printIssues(w io.Writer, issues []git.GithubIssueResponse, format, requestedColumns string) error {
+	columns, err := selectIssueColumns(requestedColumns)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "table":
+		writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		var headers []string
+		for _, column := range columns {
+			headers = append(headers, strings.ToUpper(column.name))
+		}
+		fmt.Fprintln(writer, strings.Join(headers, "\t"))
+
+		for _, issue := range issues {
+			var row []string
+			for _, column := range columns {
+				row = append(row, strings.ReplaceAll(shortColumnText(column, column.value(issue)), "\t", " "))
+			}
+			fmt.Fprintln(writer, strings.Join(row, "\t"))
+		}
+		return writer.Flush()
+
+	case "json":
+		rows := []map[string]any{}
+		for _, issue := range issues {
+			row := map[string]any{}
+			for _, column := range columns {
+				row[column.name] = column.value(issue)
+			}
+			rows = append(rows, row)
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(rows)
+
+	case "csv":
+		writer := csv.NewWriter(w)
+		var headers []string
+		for _, column := range columns {
+			headers = append(headers, column.name)
+		}
+		writer.Write(headers)
+
+		for _, issue := range issues {
+			var row []string
+			for _, column := range columns {
+				row = append(row, columnText(column.value(issue)))
+			}
+			writer.Write(row)
+		}
+		writer.Flush()
+		return writer.Error()
+
+	case "markdown", "md":
+		var headers, divider []string
+		for _, column := range columns {
+			headers = append(headers, column.name)
+			divider = append(divider, "---")
+		}
+		fmt.Fprintf(w, "| %s |\n", strings.Join(headers, " | "))
+		fmt.Fprintf(w, "| %s |\n", strings.Join(divider, " | "))
+
+		for _, issue := range issues {
+			var row []string
+			for _, column := range columns {
+				text := shortColumnText(column, column.value(issue))
+				row = append(row, strings.ReplaceAll(strings.ReplaceAll(text, "|", `\|`), "\n", " "))
+			}
+			fmt.Fprintf(w, "| %s |\n", strings.Join(row, " | "))
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unknown format %q, choose from %s", format, strings.Join(issueFormats, ", "))
+}
+
+// The original --get output, numbered by where each issue was in the list. The state is coloured when it was asked for
+func printIssueDetails(issues []git.GithubIssueResponse, positions []int, closedFlag, openFlag bool) {
+	for index, issue := range issues {
+		state := issue.State
+		switch {
+		case closedFlag && issue.State == "closed":
+			state = aphrodite.ReturnWarning(issue.State)
+		case openFlag && issue.State == "open":
+			state = aphrodite.ReturnInfo(issue.State)
+		}
+
+		fmt.Printf("%d The issue title is:\n%s\nThe body is: %s\nThe status is: %s\n\n", positions[index], strings.TrimSpace(issue.Title), issue.Body, state)
+		fmt.Printf("______________\n")
+	}
+}
+
+// Read the value that follows a flag such as --format json, returns an empty string if the flag isn't there
+func flagValue(arguments []string, names ...string) (string, error) {
+	for index, argument := range arguments {
+		for _, name := range names {
+			if argument == name {
+				if index+1 >= len(arguments) {
+					return "", fmt.Errorf("%s needs a value", name)
+				}
+				return arguments[index+1], nil
+			}
+			if value, found := strings.CutPrefix(argument, name+"="); found {
+				return value, nil
+			}
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jonathon-chew/Thoth/git"
+)
+
+func TestSelectIssueColumns(t *testing.T) {
+	tests := []struct {
+		requested string
+		want      []string
+		fails     bool
+	}{
+		{"", defaultIssueColumns, false},
+		{"number,title", []string{"number", "title"}, false},
+		{" URL , Number ", []string{"url", "number"}, false},
+		{"number,milestone", nil, true},
+		{"number,", nil, true},
+	}
+
+	for _, test := range tests {
+		columns, err := selectIssueColumns(test.requested)
+		if (err != nil) != test.fails {
+			t.Errorf("selectIssueColumns(%q) error = %v, want failure %v", test.requested, err, test.fails)
+			continue
+		}
+
+		var got []string
+		for _, column := range columns {
+			got = append(got, column.name)
+		}
+		if strings.Join(got, ",") != strings.Join(test.want, ",") {
+			t.Errorf("selectIssueColumns(%q) = %v, want %v", test.requested, got, test.want)
+		}
+	}
+}
+
+var formatIssues = []git.GithubIssueResponse{
+	{
+		Number:     7,
+		Title:      " Handle | empty config\n",
+		State:      "open",
+		Updated_at: "2026-03-01T10:00:00Z",
+		Labels:     []git.Github_Label{{Name: "bug"}, {Name: "p1"}},
+		Html_url:   "https://github.com/owner/repo/issues/7",
+	},
+	{Number: 8, Title: "No labels", State: "closed", Updated_at: "2026-03-02T10:00:00Z"},
+}
+
+func TestPrintIssuesJSON(t *testing.T) {
+	var output bytes.Buffer
+	if err := printIssues(&output, formatIssues, "json", "number,title,labels,updated"); err != nil {
+		t.Fatal(err)
+	}
+
+	var rows []map[string]any
+	if err := json.Unmarshal(output.Bytes(), &rows); err != nil {
+		t.Fatalf("the output isn't JSON: %v\n%s", err, output.String())
+	}
+	if len(rows) != 2 || len(rows[0]) != 4 {
+		t.Fatalf("unexpected rows %v", rows)
+	}
+
+	first := rows[0]
+	if first["number"] != float64(7) || first["title"] != "Handle | empty config" || first["updated"] != "2026-03-01T10:00:00Z" {
+		t.Errorf("unexpected first row %v", first)
+	}
+	if labels, _ := first["labels"].([]any); len(labels) != 2 || labels[0] != "bug" {
+		t.Errorf("labels should be a list, got %v", first["labels"])
+	}
+	if labels, _ := rows[1]["labels"].([]any); labels == nil || len(labels) != 0 {
+		t.Errorf("an issue without labels should have an empty list, got %v", rows[1]["labels"])
+	}
+
+	output.Reset()
+	if err := printIssues(&output, nil, "json", ""); err != nil || strings.TrimSpace(output.String()) != "[]" {
+		t.Errorf("no issues should be an empty array, got %q (%v)", output.String(), err)
+	}
+}
+
+func TestPrintIssuesText(t *testing.T) {
+	var output bytes.Buffer
+	if err := printIssues(&output, formatIssues, "csv", "number,labels"); err != nil {
+		t.Fatal(err)
+	}
+	if want := "number,labels\n7,\"bug, p1\"\n8,\n"; output.String() != want {
+		t.Errorf("csv is %q, want %q", output.String(), want)
+	}
+
+	output.Reset()
+	if err := printIssues(&output, formatIssues[:1], "markdown", "title,updated"); err != nil {
+		t.Fatal(err)
+	}
+	if want := "| title | updated |\n| --- | --- |\n| Handle \\| empty config | 2026-03-01 |\n"; output.String() != want {
+		t.Errorf("markdown is %q, want %q", output.String(), want)
+	}
+
+	if err := printIssues(&output, formatIssues, "yaml", ""); err == nil {
+		t.Error("an unknown format should be an error")
+	}
+	if err := printIssues(&output, formatIssues, "json", "nope"); err == nil {
+		t.Error("an unknown column should be an error")
+	}
+}
@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	aphrodite "github.com/jonathon-chew/Aphrodite"
+	"github.com/jonathon-chew/Thoth/config"
+	"github.com/jonathon-chew/Thoth/git"
+	"github.com/jonathon-chew/Thoth/scanner"
+)
+
+// The hooks thoth can install, each is a small script calling thoth hooks run <name>
+var hookNames = []string{"pre-commit", "pre-push"}
+
+// The second line of every hook thoth writes, how a hook is known to be safe to replace or remove
+const hookMarker = "# Installed by thoth hooks install, thoth hooks uninstall removes it"
+
+// thoth hooks <install|uninstall|run>
+func hooksCommand(arguments []string) error {
+	if len(arguments) == 0 {
+		return errors.New("hooks expects a sub command: install, uninstall or run")
+	}
+
+	switch arguments[0] {
+	case "install":
+		return hooksInstall(arguments[1:])
+	case "uninstall", "remove":
+		return hooksUninstall(arguments[1:])
+	case "run":
+		return hooksRun(arguments[1:])
+	}
+
+	return fmt.Errorf("%s is not a hooks sub command", arguments[0])
+}
+
+// The hooks named by --pre-commit / --pre-push, both when neither is passed in
+func selectedHooks(arguments []string) []string {
+	var selected []string
+	for _, name := range hookNames {
+		if slices.Contains(arguments, "--"+name) {
+			selected = append(selected, name)
+		}
+	}
+
+	if len(selected) == 0 {
+		return hookNames
+	}
+	return selected
+}
+
+// thoth hooks install [--pre-commit] [--pre-push] [--chain | --force]
+// An existing hook thoth didn't write is left alone unless --chain (keep it as <hook>.local and run it first) or --force (replace it, keeping a .backup)
+func hooksInstall(arguments []string) error {
+	chain, force := slices.Contains(arguments, "--chain"), slices.Contains(arguments, "--force")
+	if chain && force {
+		return errors.New("use either --chain or --force, not both")
+	}
+
+	directory, err := git.HooksDirectory()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return err
+	}
+
+	thoth, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range selectedHooks(arguments) {
+		path := filepath.Join(directory, name)
+
+		existing, err := os.ReadFile(path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist), err == nil && strings.Contains(string(existing), hookMarker):
+			// Nothing there, or an older thoth hook to update
+		case err != nil:
+			return err
+		case chain:
+			if _, err := os.Stat(path + ".local"); err == nil {
+				return fmt.Errorf("%s.local already exists so %s can't be chained, move one of them first", path, name)
+			}
+			if err := os.Rename(path, path+".local"); err != nil {
+				return err
+			}
+			aphrodite.PrintInfo(fmt.Sprintf("Kept the existing %s hook as %s.local, it runs first\n", name, name))
+		case force:
+			if err := os.WriteFile(path+".backup", existing, 0755); err != nil {
+				return err
+			}
+			aphrodite.PrintWarning(fmt.Sprintf("Replaced the existing %s hook, the old one is in %s.backup\n", name, name))
+		default:
+			return fmt.Errorf("there is already a %s hook at %s, pass --chain to run it before thoth or --force to replace it", name, path)
+		}
+
+		if err := os.WriteFile(path, []byte(hookScript(name, thoth)), 0755); err != nil {
+			return err
+		}
+		fmt.Printf("Installed %s\n", path)
+	}
+
+	settings, err := config.Load(".")
+	if err != nil {
+		return err
+	}
+	fmt.Printf("The hooks.mode in %s is %s\n", config.FileName, settings.Hooks.Mode)
+
+	return nil
+}
+
+// thoth is run from the PATH when it's there, otherwise from where it was when the hook was installed
+// pre-push is given the refs being pushed on stdin, so that is read once and handed to both the chained hook and thoth
+func hookScript(name, thoth string) string {
+	var script strings.Builder
+
+	fmt.Fprintf(&script, "#!/bin/sh\n%s\n\n", hookMarker)
+	fmt.Fprintf(&script, "thoth=thoth\ncommand -v thoth >/dev/null 2>&1 || thoth='%s'\n\n", strings.ReplaceAll(thoth, "'", `'\''`))
+
+	if name == "pre-push" {
+		script.WriteString("input=$(cat)\n\nif [ -x \"$0.local\" ]; then\n\tprintf '%s\\n' \"$input\" | \"$0.local\" \"$@\" || exit $?\nfi\n\n")
+		fmt.Fprintf(&script, "printf '%%s\\n' \"$input\" | \"$thoth\" hooks run %s \"$@\"\n", name)
+	} else {
+		script.WriteString("if [ -x \"$0.local\" ]; then\n\t\"$0.local\" \"$@\" || exit $?\nfi\n\n")
+		fmt.Fprintf(&script, "exec \"$thoth\" hooks run %s \"$@\"\n", name)
+	}
+
+	return script.String()
+}
+
+// thoth hooks uninstall [--pre-commit] [--pre-push]
+// Only hooks thoth wrote are removed, a chained <hook>.local is put back in place
+func hooksUninstall(arguments []string) error {
+	directory, err := git.HooksDirectory()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range selectedHooks(arguments) {
+		path := filepath.Join(directory, name)
+
+		existing, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !strings.Contains(string(existing), hookMarker) {
+			aphrodite.PrintWarning(fmt.Sprintf("%s wasn't installed by thoth so it was left alone\n", path))
+			continue
+		}
+
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		fmt.Printf("Removed %s\n", path)
+
+		if _, err := os.Stat(path + ".local"); err == nil {
+			if err := os.Rename(path+".local", path); err != nil {
+				return err
+			}
+			fmt.Printf("Put the chained %s hook back\n", name)
+		}
+	}
+
+	return nil
+}
+
+// thoth hooks run <pre-commit|pre-push>, what the installed hooks call
+func hooksRun(arguments []string) error {
+	if len(arguments) == 0 {
+		return errors.New("hooks run expects the hook name: pre-commit or pre-push")
+	}
+
+	settings, err := config.Load(".")
+	if err != nil {
+		return err
+	}
+
+	switch arguments[0] {
+	case "pre-commit":
+		return preCommitHook(settings.Hooks)
+	case "pre-push":
+		return prePushHook()
+	}
+
+	return fmt.Errorf("%s is not a hook thoth runs", arguments[0])
+}
+
+func preCommitHook(settings config.Hooks) error {
+	changed, err := git.DiffChangedLines("", true)
+	if err != nil {
+		return err
+	}
+
+	todos, err := findUnnumberedTODOs(changed, "")
+	if err != nil || len(todos) == 0 {
+		return err
+	}
+
+	if settings.Mode == "block" {
+		return blockedByTODOs(todos, "run thoth scan --staged to make their issues")
+	}
+
+	// Make the issues the same way thoth scan --staged does, it numbers the staged lines as well as the working tree
+	// so the commit gets the numbers and nothing held back from it is staged
+	thoth, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	scan := exec.Command(thoth, "scan", "--staged")
+	scan.Stdin, scan.Stdout, scan.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := scan.Run(); err != nil {
+		return fmt.Errorf("thoth scan --staged failed so the commit was stopped: %w", err)
+	}
+
+	// Anything still without a number couldn't be made into an issue
+	changed, err = git.DiffChangedLines("", true)
+	if err != nil {
+		return err
+	}
+	if todos, err = findUnnumberedTODOs(changed, ""); err != nil || len(todos) == 0 {
+		return err
+	}
+	return blockedByTODOs(todos, "their issues couldn't be made")
+}
+
+// Commits already made can't be changed here, so pushing is always blocked until the TODOs are numbered in a new commit
+func prePushHook() error {
+	var todos []scanner.Finding
+
+	// Each line is: <local ref> <local commit> <remote ref> <remote commit>
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 4 || strings.Trim(fields[1], "0") == "" {
+			// A branch being deleted has nothing to check
+			continue
+		}
+
+		changed, err := git.PushChangedLines(fields[1], fields[3])
+		if err != nil {
+			return err
+		}
+
+		found, err := findUnnumberedTODOs(changed, fields[1])
+		if err != nil {
+			return err
+		}
+		todos = append(todos, found...)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if len(todos) == 0 {
+		return nil
+	}
+	return blockedByTODOs(todos, "run thoth scan and commit the numbered lines before pushing")
+}
+
+// Reads each changed file as it is in the revision and returns the changed lines holding a TODO without a number
+// The files thoth scan skips are skipped here too, so the hook never asks for a TODO the scan can't number
+func findUnnumberedTODOs(changed git.ChangedLines, revision string) ([]scanner.Finding, error) {
+	found, err := scanRevision(changed, revision)
+
+	var todos []scanner.Finding
+	for _, finding := range found {
+		if finding.IssueNumber == 0 {
+			todos = append(todos, finding)
+		}
+	}
+
+	return todos, err
+}
+
+func blockedByTODOs(todos []scanner.Finding, advice string) error {
+	aphrodite.PrintError(fmt.Sprintf("%d TODO without an issue number:\n", len(todos)))
+	for _, todo := range todos {
+		fmt.Printf("  %s:%d: %s\n", todo.Path, todo.Line, strings.TrimSpace(todo.Source))
+	}
+
+	return fmt.Errorf("stopped because of TODOs without an issue number, %s (or skip the hook with --no-verify)", advice)
+}
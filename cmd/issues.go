@@ -0,0 +1,271 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	aphrodite "github.com/jonathon-chew/Aphrodite"
+	utils "github.com/jonathon-chew/Thoth/Utils"
+	"github.com/jonathon-chew/Thoth/git"
+	"github.com/jonathon-chew/Thoth/scanner"
+)
+
+// Flags for issues list that are followed by a value, so the value isn't mistaken for part of the query
+var issueListValueFlags = []string{"--sort", "--order", "--limit", "--format", "-format", "-f", "--columns", "-columns"}
+
+// thoth issues <sub command>
+func issuesCommand(arguments []string) error {
+	if len(arguments) == 0 {
+		return errors.New("issues expects a sub command: list, view, create, comment, edit, close or reopen")
+	}
+
+	switch arguments[0] {
+	case "list", "ls":
+		return issuesList(arguments[1:])
+	case "view", "show":
+		return issuesView(arguments[1:])
+	case "create", "new":
+		return issuesCreate(arguments[1:])
+	case "comment":
+		return issuesComment(arguments[1:])
+	case "edit":
+		return issuesEdit(arguments[1:])
+	case "close":
+		return issuesClose(arguments[1:])
+	case "reopen":
+		return issuesReopen(arguments[1:])
+	}
+
+	return fmt.Errorf("%s is not an issues sub command", arguments[0])
+}
+
+func issuesList(arguments []string) error {
+	query, err := git.ParseIssueQuery(queryTerms(arguments, issueListValueFlags))
+	if err != nil {
+		return err
+	}
+
+	sort, err := flagValue(arguments, "--sort")
+	if err != nil {
+		return err
+	}
+	if sort != "" {
+		if err := query.SetSort(sort); err != nil {
+			return err
+		}
+	}
+
+	order, err := flagValue(arguments, "--order")
+	if err != nil {
+		return err
+	}
+	switch order {
+	case "":
+	case "asc", "desc":
+		query.Order = order
+	default:
+		return fmt.Errorf("unknown order %q, use asc or desc", order)
+	}
+
+	limit, err := flagValue(arguments, "--limit")
+	if err != nil {
+		return err
+	}
+	if limit != "" {
+		query.Limit, err = strconv.Atoi(limit)
+		if err != nil || query.Limit < 1 {
+			return fmt.Errorf("--limit should be a positive number, not %s", limit)
+		}
+	}
+
+	format, err := flagValue(arguments, "--format", "-format", "-f")
+	if err != nil {
+		return err
+	}
+	if format == "" {
+		format = "table"
+	}
+
+	columns, err := flagValue(arguments, "--columns", "-columns")
+	if err != nil {
+		return err
+	}
+
+	credentials, err := git.GenericGitRequest()
+	if err != nil {
+		return err
+	}
+
+	issues, err := git.SearchIssues(credentials, query)
+	if errors.Is(err, git.ErrNoIssues) {
+		aphrodite.PrintWarning("no issues matched the query\n")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return printIssues(os.Stdout, issues, format, columns)
+}
+
+// Everything that isn't a flag or the value of a flag in valueFlags
+func queryTerms(arguments []string, valueFlags []string) []string {
+	var terms []string
+
+	for index := 0; index < len(arguments); index++ {
+		argument := arguments[index]
+
+		if strings.HasPrefix(argument, "-") {
+			for _, flag := range valueFlags {
+				if argument == flag {
+					index++
+					break
+				}
+			}
+			continue
+		}
+
+		terms = append(terms, argument)
+	}
+
+	return terms
+}
+
+// The issue number is the first argument, with or without a leading #
+func issueNumberArgument(arguments []string) (int, error) {
+	if len(arguments) == 0 || strings.HasPrefix(arguments[0], "-") {
+		return 0, errors.New("please pass in the issue number")
+	}
+
+	number, err := strconv.Atoi(strings.TrimPrefix(arguments[0], "#"))
+	if err != nil || number < 1 {
+		return 0, fmt.Errorf("%s is not an issue number", arguments[0])
+	}
+
+	return number, nil
+}
+
+// A comment or an event, so both can be shown in one timeline in the order they happened
+type timelineEntry struct {
+	at      time.Time
+	comment *git.Github_Comment
+	event   *git.Github_Event
+}
+
+func issuesView(arguments []string) error {
+	number, err := issueNumberArgument(arguments)
+	if err != nil {
+		return err
+	}
+
+	credentials, err := git.GenericGitRequest()
+	if err != nil {
+		return err
+	}
+
+	issue, err := git.GetIssue(credentials, number)
+	if err != nil {
+		return err
+	}
+
+	comments, events, err := git.GetIssueTimeline(credentials, number)
+	if err != nil {
+		return err
+	}
+
+	title, _ := aphrodite.ReturnBold("Cyan", fmt.Sprintf("#%d %s", issue.Number, strings.TrimSpace(issue.Title)))
+	fmt.Println(title)
+
+	state := aphrodite.ReturnInfo(issue.State)
+	if issue.State != "open" {
+		state = aphrodite.ReturnWarning(issue.State)
+	}
+	fmt.Printf("%s opened by %s on %s\n", state, issue.User.Login, shortDate(issue.Created_at))
+
+	if labels := columnText(issueColumnValue("labels", issue)); labels != "" {
+		fmt.Printf("Labels: %s\n", labels)
+	}
+	if assignees := columnText(issueColumnValue("assignees", issue)); assignees != "" {
+		fmt.Printf("Assignees: %s\n", assignees)
+	}
+	if issue.Html_url != "" {
+		fmt.Printf("%s\n", issue.Html_url)
+	}
+
+	fmt.Println()
+	if strings.TrimSpace(issue.Body) == "" {
+		fmt.Println("No description provided.")
+	} else {
+		fmt.Print(utils.RenderMarkdown(issue.Body))
+	}
+
+	var timeline []timelineEntry
+	for index := range comments {
+		timeline = append(timeline, timelineEntry{at: parseAPITime(comments[index].Created_at), comment: &comments[index]})
+	}
+	for index := range events {
+		timeline = append(timeline, timelineEntry{at: parseAPITime(events[index].Created_at), event: &events[index]})
+	}
+	sort.SliceStable(timeline, func(i, j int) bool { return timeline[i].at.Before(timeline[j].at) })
+
+	for _, entry := range timeline {
+		if entry.event != nil {
+			fmt.Printf("\n%s %s %s\n", aphrodite.ReturnWarning("•"), entry.event.Actor.Login, entry.event.Describe()+" on "+shortDate(entry.event.Created_at))
+			continue
+		}
+
+		author, _ := aphrodite.ReturnBold("White", entry.comment.User.Login)
+		fmt.Printf("\n%s commented on %s\n", author, shortDate(entry.comment.Created_at))
+		fmt.Print(utils.RenderMarkdown(entry.comment.Body))
+	}
+
+	fmt.Println()
+	// Found the same way the scan numbers them, so a numbered FIXME, HACK or XXX shows up too
+	findings, _ := scanner.New(scanner.Options{Keywords: reportKeywords}).ScanTree(".")
+
+	var linked []scanner.Finding
+	for _, finding := range findings {
+		if finding.IssueNumber == number {
+			linked = append(linked, finding)
+		}
+	}
+
+	if len(linked) == 0 {
+		fmt.Printf("Nothing numbered (#%d) was found in this directory\n", number)
+		return nil
+	}
+
+	aphrodite.PrintBold("Cyan", "In the code\n")
+	for _, finding := range linked {
+		fmt.Printf("%s:%d: %s\n", finding.Path, finding.Line, strings.TrimSpace(finding.Source))
+	}
+
+	return nil
+}
+
+func issueColumnValue(name string, issue git.GithubIssueResponse) any {
+	for _, column := range issueColumns {
+		if column.name == name {
+			return column.value(issue)
+		}
+	}
+	return nil
+}
+
+// Both trackers use RFC 3339 though GitLab adds milliseconds
+func parseAPITime(value string) time.Time {
+	parsed, _ := time.Parse(time.RFC3339, value)
+	return parsed
+}
+
+func shortDate(value string) string {
+	if parsed := parseAPITime(value); !parsed.IsZero() {
+		return parsed.Local().Format("2006-01-02 15:04")
+	}
+	return value
+}
@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	aphrodite "github.com/jonathon-chew/Aphrodite"
+	utils "github.com/jonathon-chew/Thoth/Utils"
+	"github.com/jonathon-chew/Thoth/git"
+	"github.com/jonathon-chew/Thoth/scanner"
+)
+
+// How many lines either side of a TODO are shown while reviewing it
+const reviewContext = 3
+
+// ErrReviewCancelled is returned when the batch isn't confirmed at the end of a review, nothing has been changed by then
+var ErrReviewCancelled = errors.New("the review was cancelled so no issues were made and no files were changed")
+
+// ReviewFindings shows each new TODO in the terminal with the lines around it and asks what to do with it: make the issue,
+// skip it this time, edit the issue first, link it to an existing issue or ignore it for good. Nothing happens until the end,
+// when the whole batch is confirmed. The decisions are returned for FileFindings along with the findings to mark thoth:ignore
+func ReviewFindings(filer *IssueFiler, findings []scanner.Finding) (map[int]Review, []scanner.Finding, error) {
+	if !utils.IsTerminal(os.Stdin) {
+		return nil, nil, errors.New("--review needs a terminal to ask on")
+	}
+
+	todos := newTODOs(findings)
+	reviewed := map[int]Review{}
+	var ignored []scanner.Finding
+
+	if len(todos) == 0 {
+		return reviewed, nil, nil
+	}
+
+	files := map[string][]string{}
+	var quit bool
+	var create, link, skip int
+	for position, index := range todos {
+		if quit {
+			reviewed[index] = Review{Skip: true}
+			skip++
+			continue
+		}
+
+		found := findings[index]
+		issue := newTODOIssue(filer, found, git.PathHistory(found.Path))
+
+		if _, read := files[found.Path]; !read {
+			contents, _ := os.ReadFile(found.Path)
+			files[found.Path] = strings.Split(string(contents), "\n")
+		}
+
+		aphrodite.PrintBold("Cyan", fmt.Sprintf("\n[%d/%d] %s:%d\n", position+1, len(todos), found.Path, found.Line))
+		printContext(files[found.Path], found.Line)
+
+		decision, err := reviewFinding(filer, &issue)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch {
+		case decision == "quit":
+			quit = true
+			reviewed[index] = Review{Skip: true}
+			skip++
+		case decision == "ignore":
+			reviewed[index] = Review{Skip: true}
+			ignored = append(ignored, found)
+		case decision == "skip":
+			reviewed[index] = Review{Skip: true}
+			skip++
+		case strings.HasPrefix(decision, "#"):
+			number, _ := strconv.Atoi(strings.TrimPrefix(decision, "#"))
+			reviewed[index] = Review{Link: number}
+			link++
+		default:
+			reviewed[index] = Review{Issue: &issue}
+			create++
+		}
+	}
+
+	if !confirmReview(create, link, len(ignored), skip) {
+		return nil, nil, ErrReviewCancelled
+	}
+
+	return reviewed, ignored, nil
+}
+
+// The lines around the TODO with their numbers, the TODO's line marked with >
+func printContext(lines []string, line int) {
+	for number := max(line-reviewContext, 1); number <= min(line+reviewContext, len(lines)); number++ {
+		text := fmt.Sprintf("%5d | %s", number, lines[number-1])
+		if number == line {
+			aphrodite.PrintBold("Yellow", ">"+text+"\n")
+		} else {
+			fmt.Println(" " + text)
+		}
+	}
+}
+
+// Asks until there's an answer: accept, skip, ignore, quit or #N to link. Editing changes the issue and asks again
+func reviewFinding(filer *IssueFiler, issue *git.Github_Issue) (string, error) {
+	for {
+		fmt.Printf("Title:  %s\n", issue.Title)
+		if len(issue.Label) > 0 {
+			fmt.Printf("Labels: %s\n", strings.Join(issue.Label, ", "))
+		}
+		if issue.DueDate != "" {
+			fmt.Printf("Due:    %s\n", issue.DueDate)
+		}
+
+		suggested, reason, found := filer.Suggest(*issue)
+		options := "[a]ccept, [s]kip, [e]dit, [l]ink, [i]gnore or [q]uit?"
+		if found {
+			fmt.Printf("Looks like #%d %s (%s)\n", suggested.Number, strings.TrimSpace(suggested.Title), reason)
+			options = fmt.Sprintf("[a]ccept, [s]kip, [e]dit, [l]ink to #%d, [i]gnore or [q]uit?", suggested.Number)
+		}
+
+		answer, err := utils.Prompt(options + " ")
+		if err != nil {
+			return "", err
+		}
+
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "a", "accept", "y", "yes":
+			return "accept", nil
+		case "s", "skip", "n", "no":
+			return "skip", nil
+		case "i", "ignore":
+			return "ignore", nil
+		case "q", "quit":
+			return "quit", nil
+		case "e", "edit":
+			if err := editReviewIssue(issue); err != nil {
+				aphrodite.PrintWarning(fmt.Sprintf("The issue wasn't changed: %v\n", err))
+			}
+		case "l", "link":
+			number, err := chooseLink(filer, suggested.Number)
+			if err != nil {
+				return "", err
+			}
+			if number != 0 {
+				return fmt.Sprintf("#%d", number), nil
+			}
+		default:
+			aphrodite.PrintWarning("Please answer a, s, e, l, i or q\n")
+		}
+	}
+}
+
+// Opens the issue in $EDITOR: the first line is the title, a Labels: line sets the labels and the rest is the body
+func editReviewIssue(issue *git.Github_Issue) error {
+	text := fmt.Sprintf("%s\n\nLabels: %s\n\n%s", issue.Title, strings.Join(issue.Label, ", "), issue.Body)
+
+	edited, err := utils.OpenEditor(text, "The first line is the title and the Labels: line is a comma separated list of labels, everything else is the body.")
+	if err != nil {
+		return err
+	}
+
+	title, rest, _ := strings.Cut(edited, "\n")
+	if strings.TrimSpace(title) == "" {
+		return errors.New("the title was empty")
+	}
+
+	var labels []string
+	var body []string
+	for _, line := range strings.Split(rest, "\n") {
+		if after, found := strings.CutPrefix(line, "Labels:"); found {
+			labels = nil
+			for _, label := range strings.Split(after, ",") {
+				if label = strings.TrimSpace(label); label != "" {
+					labels = append(labels, label)
+				}
+			}
+			continue
+		}
+		body = append(body, line)
+	}
+
+	issue.Title, issue.Label, issue.Body = strings.TrimSpace(title), labels, strings.TrimSpace(strings.Join(body, "\n"))
+	return nil
+}
+
+// Asks for the issue to link to, 0 goes back to the other choices. Issues that weren't fetched are looked up to check they exist
+func chooseLink(filer *IssueFiler, suggested int) (int, error) {
+	message := "Link to which issue number? (blank to go back) "
+	if suggested != 0 {
+		message = fmt.Sprintf("Link to which issue number? [%d] ", suggested)
+	}
+
+	for {
+		answer, err := utils.Prompt(message)
+		if err != nil {
+			return 0, err
+		}
+
+		answer = strings.TrimPrefix(strings.TrimSpace(answer), "#")
+		if answer == "" {
+			return suggested, nil
+		}
+
+		number, err := strconv.Atoi(answer)
+		if err != nil || number < 1 {
+			aphrodite.PrintWarning("Please give an issue number\n")
+			continue
+		}
+
+		if _, found := filer.known(number); found {
+			return number, nil
+		}
+		if _, err := git.GetIssue(filer.Credentials, number); err != nil {
+			aphrodite.PrintWarning(fmt.Sprintf("Unable to find issue #%d: %v\n", number, err))
+			continue
+		}
+		return number, nil
+	}
+}
+
+// Sums up what the review decided and asks before any of it is done
+func confirmReview(create, link, ignore, skip int) bool {
+	if create == 0 && link == 0 && ignore == 0 {
+		aphrodite.PrintInfo("Nothing to do, every TODO was skipped\n")
+		return true
+	}
+
+	answer, err := utils.Prompt(fmt.Sprintf("\nMake %d issues, link %d TODOs to existing issues, ignore %d and skip %d? [Y/n] ", create, link, ignore, skip))
+	if err != nil {
+		return false
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "" || answer == "y" || answer == "yes"
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	utils "github.com/jonathon-chew/Thoth/Utils"
+	"github.com/jonathon-chew/Thoth/git"
+	"github.com/jonathon-chew/Thoth/scanner"
+)
+
+// ReviewRequested is true when thoth scan was asked to go through each new TODO before filing, see ReviewFindings
+func ReviewRequested(arguments []string) bool {
+	return slices.ContainsFunc(arguments, func(argument string) bool {
+		return slices.Contains([]string{"--review", "-review", "--interactive", "-i"}, argument)
+	})
+}
+
+// StagedRequested is true when thoth scan was asked for the staged lines only, see ScanStaged
+func StagedRequested(arguments []string) bool {
+	return slices.ContainsFunc(arguments, func(argument string) bool {
+		return slices.Contains([]string{"--staged", "-staged", "--cached"}, argument)
+	})
+}
+
+// ScanStaged finds the TODOs on the staged lines in the staged version of each file, as the commit will have them.
+// They are returned twice, in the same order: at their line in the working tree and at their line in the staged file,
+// so each can be numbered in both without staging anything else. A TODO whose line was changed again since it was
+// staged can't be followed to the working tree, so it is left out with a warning until it is staged again
+func ScanStaged(changed git.ChangedLines) ([]scanner.Finding, []scanner.Finding, error) {
+	found, err := scanRevision(changed, "")
+
+	var findings, staged []scanner.Finding
+	lineMaps := map[string]git.LineMap{}
+	for _, finding := range found {
+		lineMap, mapped := lineMaps[finding.Path]
+		if !mapped {
+			var mapErr error
+			if lineMap, mapErr = git.UnstagedLineMap(finding.Path); mapErr != nil {
+				return findings, staged, mapErr
+			}
+			lineMaps[finding.Path] = lineMap
+		}
+
+		line, found := lineMap.WorkingLine(finding.Line)
+		if !found {
+			fmt.Printf("[WARNING]: %s line %d was changed after it was staged, stage it again to make its issue: %s\n", finding.Path, finding.Line, strings.TrimSpace(finding.Source))
+			continue
+		}
+
+		staged = append(staged, finding)
+		finding.Line = line
+		findings = append(findings, finding)
+	}
+
+	return findings, staged, err
+}
+
+// scanRevision finds the TODOs on the changed lines of each file as it is in the revision, the staged version when revision is empty.
+// The files ScanTree would skip, by their name, the ignore file or being binary, are skipped here too
+func scanRevision(changed git.ChangedLines, revision string) ([]scanner.Finding, error) {
+	var findings []scanner.Finding
+
+	paths := make([]string, 0, len(changed))
+	for path := range changed {
+		if scanner.Wanted(path) {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	// Run from the top of the repository, or the folder thoth scan was run in, where the ignore file is
+	ignore, err := scanner.LoadIgnore(".")
+	if err != nil {
+		return findings, err
+	}
+
+	todoScanner := scanner.New(scanner.Options{Lines: changed, Ignore: ignore})
+	for _, path := range paths {
+		contents, err := git.FileAt(revision, path)
+		if err != nil {
+			return findings, err
+		}
+		if utils.IsBinary([]byte(contents)) {
+			continue
+		}
+
+		found, err := todoScanner.ScanReader(path, strings.NewReader(contents))
+		if err != nil {
+			return findings, err
+		}
+		findings = append(findings, found...)
+	}
+
+	return findings, nil
+}
+
+// ScanFilter reads the thoth scan flags, --since <ref> or --staged, into the lines the scan is limited to
+// nil means nothing was passed in and every line is scanned
+func ScanFilter(arguments []string) (git.ChangedLines, error) {
+	var since string
+	var staged bool
+
+	for index := 0; index < len(arguments); index++ {
+		argument := arguments[index]
+
+		switch {
+		case argument == "--since" || argument == "-since":
+			if index+1 >= len(arguments) {
+				return nil, fmt.Errorf("%s needs a git ref, e.g. origin/main", argument)
+			}
+			index++
+			since = arguments[index]
+		case strings.HasPrefix(argument, "--since="):
+			since = strings.TrimPrefix(argument, "--since=")
+		case StagedRequested([]string{argument}):
+			staged = true
+		case ReviewRequested([]string{argument}):
+			// Read by ReviewRequested, it doesn't change which lines are scanned
+		default:
+			return nil, fmt.Errorf("%s is not a scan flag, use --since <ref>, --staged, --review or --workspace <folder>", argument)
+		}
+	}
+
+	switch {
+	case since != "" && staged:
+		return nil, errors.New("use either --since or --staged, not both")
+	case since != "" || staged:
+		return git.DiffChangedLines(since, staged)
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	aphrodite "github.com/jonathon-chew/Aphrodite"
+	"github.com/jonathon-chew/Thoth/git"
+)
+
+// NumberedTODO is a (#N) TODO found in the code, with where it is now
+type NumberedTODO struct {
+	Number int
+	Line   string
+	Path   string
+	LineNo int
+	Paths  []string // The path history, see git.PathHistory
+}
+
+// Sync copies changes to a numbered TODO back to its open issue: new wording updates the title (or is commented, see
+// the sync settings) and a move updates the file and line in the body. Closed issues, issues not fetched and issues that
+// weren't made for this TODO are left alone
+func (filer *IssueFiler) Sync(todo NumberedTODO) error {
+	filer.lock.Lock()
+	index := filer.candidateIndex(todo.Number)
+	var issue git.GithubIssueResponse
+	if index >= 0 {
+		issue = filer.candidates[index]
+	}
+	filer.lock.Unlock()
+
+	if index < 0 || issue.State != "open" {
+		return nil
+	}
+
+	// Only the TODO the issue was made for speaks for it, the one its fingerprint still matches. An issue without a
+	// fingerprint was written by someone or linked to as a duplicate, and other TODOs linked to the issue don't match, so
+	// they don't take turns rewriting it
+	previous, _ := git.ParseFingerprint(issue.Body)
+	fingerprint := git.NewFingerprint(todo.Line, todo.Paths)
+	if _, matches := git.FindByFingerprint(fingerprint, []git.GithubIssueResponse{issue}); !matches {
+		return nil
+	}
+
+	title := strings.TrimSpace(strings.Replace(todo.Line, fmt.Sprintf("(#%d) ", todo.Number), "", 1))
+
+	var changes git.IssueChanges
+	var changed bool
+
+	textChanged := fingerprint.Text != previous.Text && filer.Settings.Sync.Titles != "off"
+	if textChanged {
+		if filer.Settings.Sync.Titles == "comment" {
+			if _, err := filer.commentOnIssue(filer.Credentials, todo.Number, fmt.Sprintf("The TODO for this issue in %s now reads:\n\n> %s", todo.Path, title)); err != nil {
+				return err
+			}
+		} else {
+			changes.Title = &title
+		}
+		changed = true
+	}
+
+	path, line, hasReference := git.ParseTODOReference(issue.Body)
+	moved := filer.Settings.Sync.References && (!hasReference || path != todo.Path || line != todo.LineNo)
+	changed = changed || moved
+
+	if !changed {
+		return nil
+	}
+
+	body := git.UpdateTODOBody(issue.Body, todo.Path, todo.LineNo, fingerprint)
+	if !filer.Settings.Sync.References && hasReference {
+		// Only the fingerprint moves on, the reference stays where it was written
+		body = git.UpdateTODOBody(issue.Body, path, line, fingerprint)
+	}
+	changes.Body = &body
+
+	updated, err := filer.editIssue(filer.Credentials, todo.Number, changes)
+	if err != nil {
+		return err
+	}
+
+	// Candidates are only ever added to so the index still points at this issue
+	filer.lock.Lock()
+	filer.candidates[index] = updated
+	filer.lock.Unlock()
+
+	if textChanged {
+		aphrodite.PrintInfo(fmt.Sprintf("#%d now matches its TODO comment, %s\n", todo.Number, title))
+	}
+	if moved && hasReference {
+		aphrodite.PrintInfo(fmt.Sprintf("#%d moved from %s:%d to %s:%d\n", todo.Number, path, line, todo.Path, todo.LineNo))
+	}
+
+	return nil
+}
+
+// The caller holds the lock
+func (filer *IssueFiler) candidateIndex(number int) int {
+	for index, candidate := range filer.candidates {
+		if candidate.Number == number {
+			return index
+		}
+	}
+	return -1
+}
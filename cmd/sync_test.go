@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jonathon-chew/Thoth/config"
+	"github.com/jonathon-chew/Thoth/git"
+)
+
+// A filer whose issue changes are recorded instead of sent
+type fakeFiler struct {
+	*IssueFiler
+	edits    []git.IssueChanges
+	comments []string
+}
+
+func newFakeFiler(sync config.Sync, issues ...git.GithubIssueResponse) *fakeFiler {
+	settings := config.Default()
+	settings.Sync = sync
+
+	fake := &fakeFiler{}
+	fake.IssueFiler = &IssueFiler{
+		Settings:   settings,
+		candidates: issues,
+		editIssue: func(credentials git.Credentials, number int, changes git.IssueChanges) (git.GithubIssueResponse, error) {
+			fake.edits = append(fake.edits, changes)
+
+			// The issue as it is afterwards, so the next sync sees the edit
+			issue, _ := fake.known(number)
+			if changes.Title != nil {
+				issue.Title = *changes.Title
+			}
+			if changes.Body != nil {
+				issue.Body = *changes.Body
+			}
+			return issue, nil
+		},
+		commentOnIssue: func(credentials git.Credentials, number int, body string) (git.Github_Comment, error) {
+			fake.comments = append(fake.comments, body)
+			return git.Github_Comment{}, nil
+		},
+	}
+	return fake
+}
+
+// An issue as thoth makes it for the TODO at path:line
+func syncedIssue(number int, state, todo, path string, line int) git.GithubIssueResponse {
+	fingerprint := git.NewFingerprint(todo, []string{path})
+	return git.GithubIssueResponse{
+		Number: number,
+		State:  state,
+		Title:  strings.TrimSpace(todo),
+		Body:   "Notes\n\n" + git.TODOReference(path, line) + "\n\n" + fingerprint.Comment() + "\n",
+	}
+}
+
+func TestSync(t *testing.T) {
+	update := config.Sync{Titles: "update", References: true}
+	original := syncedIssue(7, "open", "// TODO: retry the request", "client.go", 10)
+	handWritten := git.GithubIssueResponse{Number: 7, State: "open", Title: "Requests should be retried when they time out", Body: "Seen in production"}
+
+	tests := []struct {
+		name      string
+		sync      config.Sync
+		issue     git.GithubIssueResponse
+		todo      NumberedTODO
+		title     string // The new title, empty when it shouldn't change
+		comment   bool
+		reference string // Where the body should point, empty when nothing should be edited
+	}{
+		{
+			name:  "unchanged",
+			sync:  update,
+			issue: original,
+			todo:  NumberedTODO{Number: 7, Line: "// (#7) TODO: retry the request", Path: "client.go", LineNo: 10},
+		},
+		{
+			name:      "reworded",
+			sync:      update,
+			issue:     original,
+			todo:      NumberedTODO{Number: 7, Line: "// (#7) TODO: retry the request twice", Path: "client.go", LineNo: 10},
+			title:     "// TODO: retry the request twice",
+			reference: "client.go:10",
+		},
+		{
+			name:      "reworded with comments",
+			sync:      config.Sync{Titles: "comment", References: true},
+			issue:     original,
+			todo:      NumberedTODO{Number: 7, Line: "// (#7) TODO: retry the request twice", Path: "client.go", LineNo: 10},
+			comment:   true,
+			reference: "client.go:10",
+		},
+		{
+			name:  "reworded with titles off",
+			sync:  config.Sync{Titles: "off", References: true},
+			issue: original,
+			todo:  NumberedTODO{Number: 7, Line: "// (#7) TODO: retry the request twice", Path: "client.go", LineNo: 10},
+		},
+		{
+			name:      "moved",
+			sync:      update,
+			issue:     original,
+			todo:      NumberedTODO{Number: 7, Line: "// (#7) TODO: retry the request", Path: "http/client.go", LineNo: 42},
+			reference: "http/client.go:42",
+		},
+		{
+			name:  "moved with references off",
+			sync:  config.Sync{Titles: "update", References: false},
+			issue: original,
+			todo:  NumberedTODO{Number: 7, Line: "// (#7) TODO: retry the request", Path: "http/client.go", LineNo: 42},
+		},
+		{
+			name:      "reworded and moved with references off",
+			sync:      config.Sync{Titles: "update", References: false},
+			issue:     original,
+			todo:      NumberedTODO{Number: 7, Line: "// (#7) TODO: retry the request twice", Path: "http/client.go", LineNo: 42},
+			title:     "// TODO: retry the request twice",
+			reference: "client.go:10",
+		},
+		{
+			name:  "no fingerprint is left alone",
+			sync:  update,
+			issue: handWritten,
+			todo:  NumberedTODO{Number: 7, Line: "// (#7) TODO: retry the request", Path: "client.go", LineNo: 10},
+		},
+		{
+			name:  "linked from another file",
+			sync:  update,
+			issue: original,
+			todo:  NumberedTODO{Number: 7, Line: "// (#7) TODO: retry the request in the server", Path: "server.go", LineNo: 3},
+		},
+		{
+			name:  "closed",
+			sync:  update,
+			issue: syncedIssue(7, "closed", "// TODO: retry the request", "client.go", 10),
+			todo:  NumberedTODO{Number: 7, Line: "// (#7) TODO: retry the request twice", Path: "http/client.go", LineNo: 42},
+		},
+		{
+			name:  "not fetched",
+			sync:  update,
+			issue: original,
+			todo:  NumberedTODO{Number: 8, Line: "// (#8) TODO: something else", Path: "client.go", LineNo: 3},
+		},
+	}
+
+	for _, test := range tests {
+		fake := newFakeFiler(test.sync, test.issue)
+		test.todo.Paths = []string{test.todo.Path}
+		if test.todo.Path == "http/client.go" {
+			test.todo.Paths = append(test.todo.Paths, "client.go")
+		}
+
+		if err := fake.Sync(test.todo); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		if test.comment != (len(fake.comments) == 1) || len(fake.comments) > 1 {
+			t.Errorf("%s: got comments %q", test.name, fake.comments)
+		}
+
+		if test.reference == "" {
+			if len(fake.edits) != 0 {
+				t.Errorf("%s: the issue shouldn't have been edited, got %+v", test.name, fake.edits)
+			}
+			continue
+		}
+		if len(fake.edits) != 1 {
+			t.Fatalf("%s: got %d edits, want 1", test.name, len(fake.edits))
+		}
+		changes := fake.edits[0]
+
+		switch {
+		case test.title == "" && changes.Title != nil:
+			t.Errorf("%s: the title shouldn't change, got %q", test.name, *changes.Title)
+		case test.title != "" && (changes.Title == nil || *changes.Title != test.title):
+			t.Errorf("%s: the title should be %q, got %v", test.name, test.title, changes.Title)
+		}
+
+		if changes.Body == nil {
+			t.Fatalf("%s: the body wasn't updated", test.name)
+		}
+		path, line, _ := git.ParseTODOReference(*changes.Body)
+		if got := fmt.Sprintf("%s:%d", path, line); got != test.reference {
+			t.Errorf("%s: the body points at %s, want %s", test.name, got, test.reference)
+		}
+		if fingerprint, found := git.ParseFingerprint(*changes.Body); !found || fingerprint.Text != git.NewFingerprint(test.todo.Line, nil).Text {
+			t.Errorf("%s: the fingerprint wasn't updated in %q", test.name, *changes.Body)
+		}
+		if !strings.HasPrefix(*changes.Body, strings.SplitN(test.issue.Body, "\n", 2)[0]) {
+			t.Errorf("%s: the rest of the body was changed: %q", test.name, *changes.Body)
+		}
+	}
+}
+
+// Issues written by hand or linked to as a duplicate never become thoth's, however many times they are synced
+func TestSyncLeavesLinkedIssuesAlone(t *testing.T) {
+	handWritten := git.GithubIssueResponse{Number: 7, State: "open", Title: "Requests should be retried when they time out", Body: "Seen in production"}
+	fake := newFakeFiler(config.Sync{Titles: "update", References: true}, handWritten)
+
+	runs := []NumberedTODO{
+		{Number: 7, Line: "// (#7) TODO: retry the request", Path: "client.go", LineNo: 10, Paths: []string{"client.go"}},
+		{Number: 7, Line: "// (#7) TODO: retry the request twice", Path: "client.go", LineNo: 12, Paths: []string{"client.go"}},
+	}
+	for _, todo := range runs {
+		if err := fake.Sync(todo); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(fake.edits) != 0 || len(fake.comments) != 0 {
+		t.Errorf("the issue was changed: %+v %q", fake.edits, fake.comments)
+	}
+	if issue, _ := fake.known(7); issue.Title != handWritten.Title || issue.Body != handWritten.Body {
+		t.Errorf("the issue is now %+v", issue)
+	}
+}
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	aphrodite "github.com/jonathon-chew/Aphrodite"
+	utils "github.com/jonathon-chew/Thoth/Utils"
+	"github.com/jonathon-chew/Thoth/config"
+	"github.com/jonathon-chew/Thoth/git"
+	"github.com/jonathon-chew/Thoth/report"
+	"github.com/jonathon-chew/Thoth/scanner"
+)
+
+// The keywords thoth todos looks for unless --keyword is passed in
+var reportKeywords = []string{"TODO", "FIXME", "HACK", "XXX"}
+
+// thoth todos [--group file|keyword|owner|age] [--format table|json|sarif|codeclimate|markdown] [--keyword TODO,FIXME] [--age] [--sort file|age] [--older-than 90d]
+// Lists the TODOs in the tree without the network and without changing any file, --age adds who added each one and when from git blame
+// SARIF and Code Climate are for GitHub code scanning and GitLab code quality, with the severities from .thoth.json
+func todosCommand(arguments []string) error {
+	if len(arguments) > 0 && arguments[0] == "history" {
+		return todosHistory(arguments[1:])
+	}
+
+	group, err := flagValue(arguments, "--group", "-group", "-g")
+	if err != nil {
+		return err
+	}
+	if group == "" {
+		group = "file"
+	}
+	if !slices.Contains(report.GroupKeys, group) {
+		return fmt.Errorf("can't group by %q, use one of %v", group, report.GroupKeys)
+	}
+
+	format, err := flagValue(arguments, "--format", "-format", "-f")
+	if err != nil {
+		return err
+	}
+	if format == "" {
+		format = "table"
+	}
+	if !slices.Contains(report.Formats, format) {
+		return fmt.Errorf("unknown format %q, use one of %v", format, report.Formats)
+	}
+
+	settings, err := config.Load(".")
+	if err != nil {
+		return err
+	}
+
+	keywords := flagValues(arguments, "--keyword")
+	if len(keywords) == 0 {
+		keywords = reportKeywords
+	}
+
+	sortBy, err := flagValue(arguments, "--sort", "-sort")
+	if err != nil {
+		return err
+	}
+	switch sortBy {
+	case "", "file", "age":
+	default:
+		return fmt.Errorf("can't sort by %q, use file or age", sortBy)
+	}
+
+	olderThan, err := flagValue(arguments, "--older-than", "-older-than")
+	if err != nil {
+		return err
+	}
+	var minimumAge time.Duration
+	if olderThan != "" {
+		if minimumAge, err = report.ParseAge(olderThan); err != nil {
+			return err
+		}
+	}
+
+	// Anything to do with age needs git blame, which is slower than the scan so is only run when asked for
+	blame := slices.Contains(arguments, "--age") || group == "age" || sortBy == "age" || olderThan != ""
+
+	findings, scanErr := scanner.New(scanner.Options{Keywords: keywords}).ScanTree(".")
+	if scanErr != nil && len(findings) == 0 {
+		return scanErr
+	}
+
+	now := time.Now()
+
+	entries := report.NewEntries(findings)
+	if blame {
+		report.Blame(entries)
+	}
+	if olderThan != "" {
+		entries = report.OlderThan(entries, minimumAge, now)
+	}
+	if sortBy == "age" {
+		report.SortByAge(entries)
+	}
+
+	groups, err := report.GroupBy(entries, group, now)
+	if err != nil {
+		return err
+	}
+
+	if err := report.Write(os.Stdout, format, group, groups, settings.Report.Severities); err != nil {
+		return err
+	}
+
+	// Files that couldn't be read don't stop the report but are still worth knowing about
+	return scanErr
+}
+
+// thoth todos history [--every commit|day|week] [--since 1y] [--format chart|sparkline|csv|json] [--keyword TODO,FIXME]
+// Counts the TODOs at commits on the current branch, the last one of each week by default, to show whether they are going up
+func todosHistory(arguments []string) error {
+	every, err := flagValue(arguments, "--every", "-every")
+	if err != nil {
+		return err
+	}
+	if every == "" {
+		every = "week"
+	}
+	if !slices.Contains(git.Samples, every) {
+		return fmt.Errorf("can't take a commit every %q, use one of %v", every, git.Samples)
+	}
+
+	format, err := flagValue(arguments, "--format", "-format", "-f")
+	if err != nil {
+		return err
+	}
+	if format == "" {
+		format = "chart"
+	}
+	if !slices.Contains(report.HistoryFormats, format) {
+		return fmt.Errorf("unknown format %q, use one of %v", format, report.HistoryFormats)
+	}
+
+	var since time.Time
+	sinceAge, err := flagValue(arguments, "--since", "-since")
+	if err != nil {
+		return err
+	}
+	if sinceAge != "" {
+		age, err := report.ParseAge(sinceAge)
+		if err != nil {
+			return err
+		}
+		since = time.Now().Add(-age)
+	}
+
+	keywords := flagValues(arguments, "--keyword")
+	if len(keywords) == 0 {
+		keywords = reportKeywords
+	}
+
+	commits, err := git.HistoryCommits(every, since)
+	if err != nil {
+		return err
+	}
+	if len(commits) == 0 {
+		aphrodite.PrintInfo("No commits to count the TODOs in\n")
+		return nil
+	}
+
+	// Today's ignore file is used for every commit
+	ignore, err := scanner.LoadIgnore(".")
+	if err != nil {
+		return err
+	}
+
+	points, err := report.History(commits, scanner.New(scanner.Options{Keywords: keywords, Ignore: ignore}), keywords)
+	if err != nil {
+		return err
+	}
+
+	counts := make([]int, len(points))
+	labels := make([]string, len(points))
+	for index, point := range points {
+		counts[index] = point.Count
+		labels[index] = point.Time.Format("2006-01-02") + " " + point.Hash[:8]
+	}
+
+	switch format {
+	case "chart":
+		utils.RenderTrend(labels, counts, 40, "")
+	case "sparkline":
+		fmt.Println(utils.Sparkline(counts))
+	default:
+		return report.WriteHistory(os.Stdout, format, points, keywords)
+	}
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	aphrodite "github.com/jonathon-chew/Aphrodite"
+	utils "github.com/jonathon-chew/Thoth/Utils"
+	"github.com/jonathon-chew/Thoth/git"
+)
+
+// How many repositories are scanned at once, each one makes up to issueWorkers issues at a time of its own
+const workspaceWorkers = 4
+
+// workspaceResult is how the scan of one repository went
+type workspaceResult struct {
+	Repo    string
+	Code    int
+	Created int
+	Output  []byte
+}
+
+// ScanWorkspace runs thoth scan in every git repository under the folder given to --workspace, each with its own remote,
+// credentials and .thoth.json, and prints what happened in each one. It returns false when --workspace wasn't passed in
+// The rest of the arguments are scan flags, such as --since origin/main, passed on to every repository
+func ScanWorkspace(arguments []string) (bool, error) {
+	workspace, err := flagValue(arguments, "--workspace", "-workspace", "-w")
+	if err != nil {
+		return true, err
+	}
+	if workspace == "" {
+		return false, nil
+	}
+
+	var scanArguments []string
+	for index := 0; index < len(arguments); index++ {
+		switch argument := arguments[index]; {
+		case argument == "--workspace" || argument == "-workspace" || argument == "-w":
+			index++
+		case strings.HasPrefix(argument, "--workspace="), strings.HasPrefix(argument, "-workspace="), strings.HasPrefix(argument, "-w="):
+		default:
+			scanArguments = append(scanArguments, argument)
+		}
+	}
+
+	if ReviewRequested(arguments) {
+		return true, errors.New("--review can't be used with --workspace, the repositories are scanned without a terminal")
+	}
+
+	// Check the flags once here rather than having every repository fail on them
+	if _, err := ScanFilter(scanArguments); err != nil {
+		return true, err
+	}
+
+	// ~ is only expanded by the shell when it is a word of its own, not in --workspace=~/src
+	if after, found := strings.CutPrefix(workspace, "~"+string(filepath.Separator)); found {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return true, err
+		}
+		workspace = filepath.Join(home, after)
+	}
+
+	repos := utils.FindGitRepos(workspace)
+	if len(repos) == 0 {
+		return true, fmt.Errorf("no git repositories found in %s", workspace)
+	}
+
+	thoth, err := os.Executable()
+	if err != nil {
+		return true, err
+	}
+
+	aphrodite.PrintInfo(fmt.Sprintf("Scanning %d repositories in %s\n", len(repos), workspace))
+
+	results := make([]workspaceResult, len(repos))
+	var printLock sync.Mutex
+
+	slots := make(chan struct{}, workspaceWorkers)
+	var workers sync.WaitGroup
+	for index, repo := range repos {
+		workers.Add(1)
+		slots <- struct{}{}
+
+		go func(index int, repo string) {
+			defer func() { <-slots; workers.Done() }()
+
+			results[index] = scanRepository(thoth, repo, scanArguments)
+
+			// Each repository's output is printed in one go so they don't interleave
+			printLock.Lock()
+			defer printLock.Unlock()
+			aphrodite.PrintBold("Cyan", repo+"\n")
+			os.Stdout.Write(results[index].Output)
+			fmt.Println()
+		}(index, repo)
+	}
+	workers.Wait()
+
+	return true, printWorkspaceSummary(results)
+}
+
+// There is no terminal on stdin, so similar issues are linked rather than asked about, as they are in CI
+func scanRepository(thoth, repo string, arguments []string) workspaceResult {
+	result := workspaceResult{Repo: repo}
+
+	scan := exec.Command(thoth, append([]string{"scan"}, arguments...)...)
+	scan.Dir = repo
+
+	var output bytes.Buffer
+	scan.Stdout, scan.Stderr = &output, &output
+
+	err := scan.Run()
+	result.Output = output.Bytes()
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		result.Code = exitErr.ExitCode()
+	case err != nil:
+		result.Code = ExitError
+		result.Output = append(result.Output, []byte(err.Error()+"\n")...)
+	}
+
+	for _, line := range strings.Split(output.String(), "\n") {
+		if strings.HasPrefix(line, git.CreatedIssue) {
+			result.Created++
+		}
+	}
+
+	return result
+}
+
+// What the exit code of a scan means, for the summary
+func exitCodeText(code int) string {
+	switch code {
+	case ExitOK:
+		return "ok"
+	case ExitNoRemote:
+		return "no remote origin"
+	case ExitNoToken:
+		return "no token"
+	case ExitAuth:
+		return "token rejected"
+	case ExitAPI:
+		return "API error"
+	}
+	return fmt.Sprintf("failed (exit code %d)", code)
+}
+
+func printWorkspaceSummary(results []workspaceResult) error {
+	sort.Slice(results, func(i, j int) bool { return results[i].Repo < results[j].Repo })
+
+	aphrodite.PrintBold("Cyan", "Summary\n")
+
+	table := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "Repository\tResult\tIssues created")
+
+	var failed, created int
+	for _, result := range results {
+		fmt.Fprintf(table, "%s\t%s\t%d\n", result.Repo, exitCodeText(result.Code), result.Created)
+		created += result.Created
+		if result.Code != ExitOK {
+			failed++
+		}
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	aphrodite.PrintInfo(fmt.Sprintf("%d issues created across %d repositories\n", created, len(results)))
+
+	if failed > 0 {
+		return fmt.Errorf("the scan failed in %d of %d repositories", failed, len(results))
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// FileName is the per repository settings file, read from the root of the repository
+const FileName = ".thoth.json"
+
+// Config holds the repository settings, anything missing from the file keeps its default
+type Config struct {
+	Duplicates Duplicates `json:"duplicates"`
+	Sync       Sync       `json:"sync"`
+	Hooks      Hooks      `json:"hooks"`
+	Report     Report     `json:"report"`
+	Check      Check      `json:"check"`
+	Metadata   Metadata   `json:"metadata"`
+}
+
+// Duplicates controls what happens when a new issue looks like one that already exists
+type Duplicates struct {
+	Threshold        float64 `json:"threshold"`          // 0 to 1, how alike two titles need to be to count
+	Action           string  `json:"action"`             // ask, link or create
+	ClosedWithinDays int     `json:"closed_within_days"` // Closed issues older than this aren't compared against
+}
+
+// Sync controls how a numbered TODO that changed in the code is copied back to its issue
+type Sync struct {
+	Titles     string `json:"titles"`     // update, comment or off
+	References bool   `json:"references"` // Keep the file and line in the issue body up to date
+}
+
+// Hooks controls what the git hooks from thoth hooks install do with a commit that adds TODOs without an issue number
+type Hooks struct {
+	Mode string `json:"mode"` // block stops the commit, create makes the issues and stages the numbered lines
+}
+
+// Report controls the thoth todos output read by code scanning tools
+type Report struct {
+	Severities map[string]string `json:"severities"` // Keyword to one of Severities, keywords not listed are info
+}
+
+// Check is the policy thoth check-todos fails a build on, each rule can be turned off
+type Check struct {
+	Keywords     []string `json:"keywords"`      // The keywords checked, TODO unless set
+	Unnumbered   bool     `json:"unnumbered"`    // Fail on TODOs without an issue number
+	ClosedIssues bool     `json:"closed_issues"` // Fail on TODOs numbered with an issue that is closed or doesn't exist
+	Expired      bool     `json:"expired"`       // Fail on TODOs with a due: or by: date that has passed
+}
+
+// Metadata controls how a TODO(p1, due:2026-12-01) comment is copied onto the issue made for it
+// The due date becomes a milestone due that day on GitHub and the issue's due date on GitLab
+type Metadata struct {
+	PriorityLabel string `json:"priority_label"` // The label for the priority, {priority} is replaced with it (p1)
+}
+
+// Label is the label for the priority
+func (metadata Metadata) Label(priority string) string {
+	return strings.ReplaceAll(metadata.PriorityLabel, "{priority}", priority)
+}
+
+// Severities are the Code Climate severity levels, least to most severe. SARIF levels are picked from them
+var Severities = []string{"info", "minor", "major", "critical", "blocker"}
+
+// Default is used for anything the settings file doesn't set
+func Default() Config {
+	return Config{
+		Duplicates: Duplicates{
+			Threshold:        0.85,
+			Action:           "ask",
+			ClosedWithinDays: 90,
+		},
+		Sync: Sync{
+			Titles:     "update",
+			References: true,
+		},
+		Hooks: Hooks{
+			Mode: "block",
+		},
+		Report: Report{
+			// Keywords set in the file are added to these rather than replacing them all
+			Severities: map[string]string{"TODO": "info", "HACK": "minor", "XXX": "major", "FIXME": "major"},
+		},
+		Check: Check{
+			Keywords:     []string{"TODO"},
+			Unnumbered:   true,
+			ClosedIssues: true,
+			Expired:      true,
+		},
+		Metadata: Metadata{
+			PriorityLabel: "priority: {priority}",
+		},
+	}
+}
+
+// Load reads FileName from the directory, no file at all is the same as an empty one
+func Load(directory string) (Config, error) {
+	config := Default()
+
+	contents, err := os.ReadFile(filepath.Join(directory, FileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return config, err
+	}
+
+	if err := json.Unmarshal(contents, &config); err != nil {
+		return config, fmt.Errorf("unable to read %s: %w", FileName, err)
+	}
+
+	return config, config.validate()
+}
+
+func (config Config) validate() error {
+	switch config.Duplicates.Action {
+	case "ask", "link", "create":
+	default:
+		return fmt.Errorf("%s: duplicates.action should be ask, link or create, not %q", FileName, config.Duplicates.Action)
+	}
+
+	switch config.Sync.Titles {
+	case "update", "comment", "off":
+	default:
+		return fmt.Errorf("%s: sync.titles should be update, comment or off, not %q", FileName, config.Sync.Titles)
+	}
+
+	switch config.Hooks.Mode {
+	case "block", "create":
+	default:
+		return fmt.Errorf("%s: hooks.mode should be block or create, not %q", FileName, config.Hooks.Mode)
+	}
+
+	for keyword, severity := range config.Report.Severities {
+		if !slices.Contains(Severities, severity) {
+			return fmt.Errorf("%s: report.severities.%s should be one of %v, not %q", FileName, keyword, Severities, severity)
+		}
+	}
+
+	if !strings.Contains(config.Metadata.PriorityLabel, "{priority}") {
+		return fmt.Errorf("%s: metadata.priority_label needs {priority} in it, not %q", FileName, config.Metadata.PriorityLabel)
+	}
+
+	if len(config.Check.Keywords) == 0 {
+		return fmt.Errorf("%s: check.keywords needs at least one keyword", FileName)
+	}
+
+	if config.Duplicates.Threshold <= 0 || config.Duplicates.Threshold > 1 {
+		return fmt.Errorf("%s: duplicates.threshold should be above 0 and at most 1, not %v", FileName, config.Duplicates.Threshold)
+	}
+
+	if config.Duplicates.ClosedWithinDays < 0 {
+		return fmt.Errorf("%s: duplicates.closed_within_days can't be negative", FileName)
+	}
+
+	return nil
+}
@@ -0,0 +1,294 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// A place a token can be found, tried in order until one returns a token
+type tokenSource struct {
+	Name   string
+	Lookup func(host string) string
+}
+
+// Order that the sources are checked for GitHub remotes - GH_PERSONAL_TOKEN stays first so existing setups keep working
+var githubTokenSources = []tokenSource{
+	envTokenSource("GH_PERSONAL_TOKEN"),
+	envTokenSource("GITHUB_TOKEN"),
+	envTokenSource("GH_TOKEN"),
+	{Name: "gh config", Lookup: ghConfigToken},
+	{Name: "git credential", Lookup: gitCredentialToken},
+	{Name: ".netrc", Lookup: netrcToken},
+}
+
+// Order that the sources are checked for GitLab remotes
+var gitlabTokenSources = []tokenSource{
+	envTokenSource("GL_PERSONAL_TOKEN"),
+	envTokenSource("GITLAB_TOKEN"),
+	{Name: "glab config", Lookup: glabConfigToken},
+	{Name: "git credential", Lookup: gitCredentialToken},
+	{Name: ".netrc", Lookup: netrcToken},
+}
+
+func envTokenSource(variable string) tokenSource {
+	return tokenSource{
+		Name: variable,
+		Lookup: func(string) string {
+			return strings.TrimSpace(os.Getenv(variable))
+		},
+	}
+}
+
+// ResolveToken walks the credential chain for the host and returns the first token found along with the name of where it came from
+func ResolveToken(host string) (string, string, error) {
+	sources := githubTokenSources
+	if isGitlabHost(host) {
+		sources = gitlabTokenSources
+	}
+
+	var checked []string
+	for _, source := range sources {
+		if token := source.Lookup(host); token != "" {
+			return token, source.Name, nil
+		}
+		checked = append(checked, source.Name)
+	}
+
+	return "", "", fmt.Errorf("%w for %s (checked %s)", ErrNoToken, host, strings.Join(checked, ", "))
+}
+
+// Split the remote origin into the host, owner and repository, handles https, ssh:// and scp style (git@host:owner/repo) remotes
+func parseRemoteURL(remote string) (string, string, string, error) {
+	remote = strings.TrimSpace(remote)
+
+	var host, path string
+	if strings.Contains(remote, "://") {
+		parsed, ErrParsingURL := url.Parse(remote)
+		if ErrParsingURL != nil {
+			return "", "", "", ErrParsingURL
+		}
+		host, path = parsed.Hostname(), parsed.Path
+	} else if at, colon := strings.Index(remote, "@"), strings.Index(remote, ":"); colon > at && colon != -1 {
+		host, path = remote[at+1:colon], remote[colon+1:]
+	} else {
+		return "", "", "", fmt.Errorf("unable to understand the remote origin %s", remote)
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	lastSlash := strings.LastIndex(path, "/")
+	if host == "" || lastSlash <= 0 || lastSlash == len(path)-1 {
+		return "", "", "", fmt.Errorf("unable to find an owner and repository in %s", remote)
+	}
+
+	// GitLab allows nested groups so everything before the last segment is the owner
+	return host, path[:lastSlash], path[lastSlash+1:], nil
+}
+
+func isGitlabHost(host string) bool {
+	return strings.Contains(host, "gitlab")
+}
+
+func isGithubHost(host string) bool {
+	return strings.Contains(host, "github")
+}
+
+func configDirectory(overrideVariable, name string) string {
+	if directory := os.Getenv(overrideVariable); directory != "" {
+		return directory
+	}
+
+	if directory := os.Getenv("XDG_CONFIG_HOME"); directory != "" {
+		return filepath.Join(directory, name)
+	}
+
+	home, ErrFindingHome := os.UserHomeDir()
+	if ErrFindingHome != nil {
+		return ""
+	}
+
+	return filepath.Join(home, ".config", name)
+}
+
+// The gh CLI keeps tokens in hosts.yml under the host name
+func ghConfigToken(host string) string {
+	directory := configDirectory("GH_CONFIG_DIR", "gh")
+	if directory == "" {
+		return ""
+	}
+	return yamlHostValue(filepath.Join(directory, "hosts.yml"), host, "oauth_token")
+}
+
+// The glab CLI keeps tokens in config.yml under hosts -> host name
+func glabConfigToken(host string) string {
+	directory := configDirectory("GLAB_CONFIG_DIR", "glab-cli")
+	if directory == "" {
+		return ""
+	}
+	return yamlHostValue(filepath.Join(directory, "config.yml"), host, "token")
+}
+
+// Small reader for the gh / glab config files, finds the host key and returns the first matching key nested below it
+// Both files are simple enough maps that pulling in a YAML library isn't worth it
+func yamlHostValue(path, host, key string) string {
+	contents, ErrReadingConfig := os.ReadFile(path)
+	if ErrReadingConfig != nil {
+		return ""
+	}
+
+	var hostIndent = -1
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+
+		if hostIndent == -1 {
+			if strings.Trim(strings.TrimSuffix(trimmed, ":"), `"'`) == host && strings.HasSuffix(trimmed, ":") {
+				hostIndent = indent
+			}
+			continue
+		}
+
+		// Left the host block without finding the key
+		if indent <= hostIndent {
+			return ""
+		}
+
+		if value, found := strings.CutPrefix(trimmed, key+":"); found {
+			return strings.Trim(strings.TrimSpace(value), `"'`)
+		}
+	}
+
+	return ""
+}
+
+// Ask git for a stored credential for the host, prompting is turned off so this never blocks waiting for the user
+func gitCredentialToken(host string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", "credential", "fill")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("protocol=https\nhost=%s\n\n", host))
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_ASKPASS=", "SSH_ASKPASS=")
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+
+	if err := cmd.Run(); err != nil {
+		return ""
+	}
+
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		if password, found := strings.CutPrefix(scanner.Text(), "password="); found {
+			return strings.TrimSpace(password)
+		}
+	}
+
+	return ""
+}
+
+// Look for a machine entry for the host (or a default entry) in the users .netrc file
+func netrcToken(host string) string {
+	path := os.Getenv("NETRC")
+	if path == "" {
+		home, ErrFindingHome := os.UserHomeDir()
+		if ErrFindingHome != nil {
+			return ""
+		}
+		path = filepath.Join(home, ".netrc")
+	}
+
+	contents, ErrReadingNetrc := os.ReadFile(path)
+	if ErrReadingNetrc != nil {
+		return ""
+	}
+
+	fields := strings.Fields(string(contents))
+
+	var inMachine bool
+	var defaultPassword string
+	var inDefault bool
+	for index := 0; index < len(fields); index++ {
+		switch fields[index] {
+		case "machine":
+			inDefault = false
+			inMachine = index+1 < len(fields) && fields[index+1] == host
+			index++
+		case "default":
+			inMachine, inDefault = false, true
+		case "password":
+			if index+1 >= len(fields) {
+				break
+			}
+			if inMachine {
+				return fields[index+1]
+			}
+			if inDefault && defaultPassword == "" {
+				defaultPassword = fields[index+1]
+			}
+			index++
+		case "login", "account":
+			index++
+		case "macdef":
+			// Macros run until a blank line which Fields has already thrown away, so stop here rather than misread them
+			return defaultPassword
+		}
+	}
+
+	return defaultPassword
+}
+
+// TokenScopes asks the API which scopes the token was granted
+// Fine-grained GitHub tokens don't report scopes so the returned list is empty with no error
+func TokenScopes(credentials Credentials) ([]string, error) {
+	var request *http.Request
+	var err error
+
+	if isGitlabHost(credentials.Host) {
+		request, err = newGitlabRequest(credentials, "GET", "/personal_access_tokens/self", nil)
+	} else {
+		request, err = newGithubRequest(credentials, "GET", "/user", nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	response, responseBody, err := Client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if isGitlabHost(credentials.Host) {
+		var tokenDetails struct {
+			Scopes []string `json:"scopes"`
+		}
+		if err := json.Unmarshal(responseBody, &tokenDetails); err != nil {
+			return nil, fmt.Errorf("error unmarshalling response: %w", err)
+		}
+		return tokenDetails.Scopes, nil
+	}
+
+	var scopes []string
+	for _, scope := range strings.Split(response.Header.Get("X-OAuth-Scopes"), ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	return scopes, nil
+}
@@ -0,0 +1,63 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseRemoteURL(t *testing.T) {
+	t.Log("Testing parseRemoteURL")
+
+	remotes := map[string][3]string{
+		"https://github.com/jonathon-chew/Thoth.git\n":         {"github.com", "jonathon-chew", "Thoth"},
+		"git@github.com:jonathon-chew/Thoth.git":               {"github.com", "jonathon-chew", "Thoth"},
+		"ssh://git@gitlab.example.com:2222/group/sub/repo.git": {"gitlab.example.com", "group/sub", "repo"},
+		"https://user@gitlab.com/group/repo":                   {"gitlab.com", "group", "repo"},
+	}
+
+	for remote, expected := range remotes {
+		host, owner, repo, err := parseRemoteURL(remote)
+		if err != nil {
+			t.Errorf("Unable to parse %q: %v", remote, err)
+			continue
+		}
+
+		if host != expected[0] || owner != expected[1] || repo != expected[2] {
+			t.Errorf("Parsing %q gave %s %s %s", remote, host, owner, repo)
+		}
+	}
+
+	if _, _, _, err := parseRemoteURL("not a remote"); err == nil {
+		t.Error("Expected an error for a malformed remote")
+	}
+}
+
+func TestTokenFromConfigFiles(t *testing.T) {
+	t.Log("Testing the gh config and .netrc token sources")
+
+	directory := t.TempDir()
+
+	hosts := "github.example.com:\n    oauth_token: wrong\ngithub.com:\n    user: thoth\n    oauth_token: \"gho_token\"\n"
+	if err := os.WriteFile(filepath.Join(directory, "hosts.yml"), []byte(hosts), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if token := yamlHostValue(filepath.Join(directory, "hosts.yml"), "github.com", "oauth_token"); token != "gho_token" {
+		t.Errorf("Expected gho_token from hosts.yml, got %q", token)
+	}
+
+	netrc := "machine gitlab.com login thoth password glpat\ndefault login anon password fallback\n"
+	if err := os.WriteFile(filepath.Join(directory, ".netrc"), []byte(netrc), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("NETRC", filepath.Join(directory, ".netrc"))
+
+	if token := netrcToken("gitlab.com"); token != "glpat" {
+		t.Errorf("Expected glpat from .netrc, got %q", token)
+	}
+
+	if token := netrcToken("github.com"); token != "fallback" {
+		t.Errorf("Expected the default entry from .netrc, got %q", token)
+	}
+}
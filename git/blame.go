@@ -0,0 +1,100 @@
+package git
+
+import (
+	"bufio"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BlameLine is what git blame knows about the commit that last changed a line
+type BlameLine struct {
+	Commit  string    `json:"commit"`
+	Author  string    `json:"author"`
+	Email   string    `json:"email"`
+	Time    time.Time `json:"time"`
+	Summary string    `json:"summary"`
+}
+
+// Committed is false for lines only in the working tree, which git blame gives an all zero commit
+func (line BlameLine) Committed() bool {
+	return strings.Trim(line.Commit, "0") != ""
+}
+
+// Blame runs git blame --porcelain on the file and returns the blame for each line, the first line at index 0
+// Files git doesn't track are an error
+func Blame(path string) ([]BlameLine, error) {
+	output, err := runGit("blame", "--porcelain", "--", path)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseBlamePorcelain(output), nil
+}
+
+// Porcelain gives a header per line: <commit> <original line> <final line> [<lines in group>]
+// followed by the commit's details the first time that commit is seen, and then the line itself after a tab
+func parseBlamePorcelain(output string) []BlameLine {
+	var lines []BlameLine
+	commits := map[string]*BlameLine{}
+
+	var current *BlameLine
+	var finalLine int
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		text := scanner.Text()
+
+		if strings.HasPrefix(text, "\t") {
+			if current != nil && finalLine > 0 {
+				for len(lines) < finalLine {
+					lines = append(lines, BlameLine{})
+				}
+				lines[finalLine-1] = *current
+			}
+			continue
+		}
+
+		key, value, _ := strings.Cut(text, " ")
+
+		if current == nil || isCommitHash(key) {
+			fields := strings.Fields(text)
+			if len(fields) >= 3 && isCommitHash(fields[0]) {
+				finalLine, _ = strconv.Atoi(fields[2])
+				if commits[fields[0]] == nil {
+					commits[fields[0]] = &BlameLine{Commit: fields[0]}
+				}
+				current = commits[fields[0]]
+			}
+			continue
+		}
+
+		switch key {
+		case "author":
+			current.Author = value
+		case "author-mail":
+			current.Email = strings.Trim(value, "<>")
+		case "author-time":
+			if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+				current.Time = time.Unix(seconds, 0)
+			}
+		case "summary":
+			current.Summary = value
+		}
+	}
+
+	return lines
+}
+
+func isCommitHash(value string) bool {
+	if len(value) != 40 && len(value) != 64 {
+		return false
+	}
+	for _, r := range value {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,40 @@
+package git
+
+import "testing"
+
+func TestParseBlamePorcelain(t *testing.T) {
+	first := "1111111111111111111111111111111111111111"
+	uncommitted := "0000000000000000000000000000000000000000"
+
+	output := first + ` 1 1 2
+author Sam Smith
+author-mail <sam@example.com>
+author-time 1700000000
+author-tz +0000
+summary Add the parser
+filename main.go
+	package main
+` + first + ` 2 2
+	// TODO: first
+` + uncommitted + ` 3 3 1
+author Not Committed Yet
+author-mail <not.committed.yet>
+author-time 1800000000
+summary Version of main.go from main.go
+filename main.go
+	// TODO: new
+`
+
+	lines := parseBlamePorcelain(output)
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3: %+v", len(lines), lines)
+	}
+
+	if lines[1].Commit != first || lines[1].Author != "Sam Smith" || lines[1].Email != "sam@example.com" || lines[1].Time.Unix() != 1700000000 || lines[1].Summary != "Add the parser" {
+		t.Errorf("line 2 didn't reuse the commit details: %+v", lines[1])
+	}
+
+	if lines[2].Committed() || !lines[0].Committed() {
+		t.Errorf("Committed is wrong: %v %v", lines[0].Committed(), lines[2].Committed())
+	}
+}
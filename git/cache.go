@@ -0,0 +1,132 @@
+package git
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// What is saved to disk for a cached GET, the ETag is sent back as If-None-Match on the next request
+type cacheEntry struct {
+	URL     string          `json:"url"`
+	ETag    string          `json:"etag"`
+	SavedAt time.Time       `json:"saved_at"`
+	Body    json.RawMessage `json:"body"`
+}
+
+// The cache lives in the users cache directory unless THOTH_CACHE_DIR is set
+func cacheDirectory() (string, error) {
+	if directory := os.Getenv("THOTH_CACHE_DIR"); directory != "" {
+		return directory, nil
+	}
+
+	directory, ErrFindingCache := os.UserCacheDir()
+	if ErrFindingCache != nil {
+		return "", ErrFindingCache
+	}
+
+	return filepath.Join(directory, "thoth"), nil
+}
+
+// Each repository gets its own folder and each URL its own file, named by a hash of the URL
+func cachePath(credentials Credentials, requestURL string) (string, error) {
+	directory, err := cacheDirectory()
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256([]byte(requestURL))
+
+	return filepath.Join(directory, credentials.Host, filepath.FromSlash(credentials.Owner), credentials.Repo, hex.EncodeToString(hash[:8])+".json"), nil
+}
+
+func readCache(path string) (cacheEntry, error) {
+	var entry cacheEntry
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return entry, err
+	}
+
+	if err := json.Unmarshal(contents, &entry); err != nil {
+		return entry, fmt.Errorf("the cache file %s is corrupt: %w", path, err)
+	}
+
+	return entry, nil
+}
+
+func writeCache(path string, entry cacheEntry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	contents, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so a half written cache is never read back
+	temporaryPath := path + ".tmp"
+	if err := os.WriteFile(temporaryPath, contents, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(temporaryPath, path)
+}
+
+// Make a GET request conditional on the cached ETag, a 304 is answered from the cache and costs no rate limit
+// Any failure to read or write the cache just means the request goes out unconditionally
+func cachedGet(credentials Credentials, request *http.Request) (*http.Response, []byte, error) {
+	path, ErrFindingCache := cachePath(credentials, request.URL.String())
+
+	var cached cacheEntry
+	if ErrFindingCache == nil {
+		if entry, err := readCache(path); err == nil && entry.ETag != "" {
+			cached = entry
+			request.Header.Set("If-None-Match", entry.ETag)
+		}
+	}
+
+	response, responseBody, err := Client.Do(request)
+	if err != nil {
+		return response, responseBody, err
+	}
+
+	if response.StatusCode == http.StatusNotModified {
+		return response, cached.Body, nil
+	}
+
+	if etag := response.Header.Get("ETag"); etag != "" && ErrFindingCache == nil && json.Valid(responseBody) {
+		writeCache(path, cacheEntry{
+			URL:     request.URL.String(),
+			ETag:    etag,
+			SavedAt: time.Now(),
+			Body:    responseBody,
+		})
+	}
+
+	return response, responseBody, nil
+}
+
+// Read what was last saved for the URL without touching the network, used by --offline
+func offlineGet(credentials Credentials, requestURL string) ([]byte, time.Time, error) {
+	path, err := cachePath(credentials, requestURL)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	entry, err := readCache(path)
+	if os.IsNotExist(err) {
+		return nil, time.Time{}, fmt.Errorf("nothing has been cached for %s/%s yet, run once with a network connection first", credentials.Owner, credentials.Repo)
+	}
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return entry.Body, entry.SavedAt, nil
+}
@@ -0,0 +1,271 @@
+package git
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APIError is returned when GitHub or GitLab answer with a status that isn't a success
+// Message is the "message" (or "error") field from the JSON body, which is usually the most useful part
+type APIError struct {
+	StatusCode int
+	Status     string
+	Method     string
+	URL        string
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%s %s returned %s", e.Method, e.URL, e.Status)
+	}
+	return fmt.Sprintf("%s %s returned %s: %s", e.Method, e.URL, e.Status, e.Message)
+}
+
+// APIClient is shared by every GitHub and GitLab call so they all get the same timeout, retry and rate limit behaviour
+type APIClient struct {
+	HTTPClient  *http.Client
+	MaxRetries  int           // How many times a request is tried again after the first attempt
+	BaseBackoff time.Duration // Doubled on every retry of a failed idempotent request
+	MaxWait     time.Duration // The longest a rate limit is waited out before giving up with the error
+
+	sleep func(time.Duration)
+}
+
+// Client is the APIClient used by the github and gitlab functions
+var Client = NewAPIClient()
+
+func NewAPIClient() *APIClient {
+	return &APIClient{
+		HTTPClient:  &http.Client{Timeout: 30 * time.Second},
+		MaxRetries:  3,
+		BaseBackoff: 500 * time.Millisecond,
+		MaxWait:     2 * time.Minute,
+		sleep:       time.Sleep,
+	}
+}
+
+// Do sends the request and returns the response with its body already read and closed
+// Network errors and 5xx responses are retried with backoff for idempotent methods, rate limits are waited out for any method
+// as the API rejected the request without acting on it. Any other non 2xx / 304 status is returned as an *APIError
+func (c *APIClient) Do(request *http.Request) (*http.Response, []byte, error) {
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && request.GetBody != nil {
+			body, ErrRewindingBody := request.GetBody()
+			if ErrRewindingBody != nil {
+				return nil, nil, ErrRewindingBody
+			}
+			request.Body = body
+		}
+
+		canRetry := attempt < c.MaxRetries
+
+		response, err := c.HTTPClient.Do(request)
+		if err != nil {
+			if canRetry && isIdempotent(request.Method) {
+				c.sleep(c.backoff(attempt))
+				continue
+			}
+			return nil, nil, err
+		}
+
+		responseBody, err := io.ReadAll(response.Body)
+		response.Body.Close()
+		if err != nil {
+			return response, nil, err
+		}
+
+		if response.StatusCode < 300 || response.StatusCode == http.StatusNotModified {
+			return response, responseBody, nil
+		}
+
+		if wait, limited := rateLimitWait(response, responseBody); limited && canRetry && wait <= c.MaxWait {
+			c.sleep(wait)
+			continue
+		}
+
+		if response.StatusCode >= 500 && canRetry && isIdempotent(request.Method) {
+			c.sleep(c.backoff(attempt))
+			continue
+		}
+
+		return response, responseBody, newAPIError(request, response, responseBody)
+	}
+}
+
+func (c *APIClient) backoff(attempt int) time.Duration {
+	return c.BaseBackoff * time.Duration(1<<attempt)
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// Work out whether the response is a rate limit and how long to wait, GitHub uses X-RateLimit-* and GitLab uses RateLimit-*
+func rateLimitWait(response *http.Response, body []byte) (time.Duration, bool) {
+	if response.StatusCode != http.StatusTooManyRequests && response.StatusCode != http.StatusForbidden {
+		return 0, false
+	}
+
+	if retryAfter := response.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if date, err := http.ParseTime(retryAfter); err == nil {
+			return max(time.Until(date), 0), true
+		}
+	}
+
+	for _, prefix := range []string{"X-RateLimit-", "RateLimit-"} {
+		if response.Header.Get(prefix+"Remaining") != "0" {
+			continue
+		}
+		if reset, err := strconv.ParseInt(response.Header.Get(prefix+"Reset"), 10, 64); err == nil {
+			return max(time.Until(time.Unix(reset, 0)), 0) + time.Second, true
+		}
+	}
+
+	// GitHub asks for at least a minute between retries when a secondary rate limit has no headers
+	if strings.Contains(strings.ToLower(string(body)), "secondary rate limit") {
+		return time.Minute, true
+	}
+
+	if response.StatusCode == http.StatusTooManyRequests {
+		return time.Minute, true
+	}
+
+	return 0, false
+}
+
+func newAPIError(request *http.Request, response *http.Response, body []byte) *APIError {
+	apiError := &APIError{
+		StatusCode: response.StatusCode,
+		Status:     response.Status,
+		Method:     request.Method,
+		URL:        request.URL.String(),
+	}
+
+	var errorBody struct {
+		Message json.RawMessage `json:"message"`
+		Error   string          `json:"error"`
+	}
+
+	if json.Unmarshal(body, &errorBody) != nil {
+		return apiError
+	}
+
+	// GitLab sometimes sends the message as an object of field errors, so fall back to the raw JSON
+	var message string
+	if json.Unmarshal(errorBody.Message, &message) == nil {
+		apiError.Message = message
+	} else if len(errorBody.Message) > 0 {
+		apiError.Message = string(errorBody.Message)
+	} else {
+		apiError.Message = errorBody.Error
+	}
+
+	return apiError
+}
+
+// The REST API lives on api.github.com for github.com and under /api/v3 for GitHub Enterprise
+func githubAPIBase(host string) string {
+	if host == "" || host == "github.com" {
+		return "https://api.github.com"
+	}
+	return fmt.Sprintf("https://%s/api/v3", host)
+}
+
+// Build a GitHub API request with the usual headers, the payload is marshalled to JSON when it isn't nil
+func newGithubRequest(credentials Credentials, method, path string, payload any) (*http.Request, error) {
+	body, err := jsonPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequest(method, githubAPIBase(credentials.Host)+path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Accept", "application/vnd.github+json")
+	request.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if credentials.Token != "" {
+		request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", credentials.Token))
+	}
+	if payload != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	return request, nil
+}
+
+// Build a GitLab API request, path is relative to /api/v4
+func newGitlabRequest(credentials Credentials, method, path string, payload any) (*http.Request, error) {
+	body, err := jsonPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequest(method, fmt.Sprintf("https://%s/api/v4%s", credentials.Host, path), body)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("PRIVATE-TOKEN", credentials.Token)
+	if payload != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	return request, nil
+}
+
+// GitLab addresses projects by their URL encoded full path
+func gitlabProjectPath(credentials Credentials) string {
+	return "/projects/" + url.PathEscape(credentials.Owner+"/"+credentials.Repo)
+}
+
+func jsonPayload(payload any) (io.Reader, error) {
+	if payload == nil {
+		return nil, nil
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(jsonData), nil
+}
+
+// Both trackers paginate with a Link header, this returns a copy of the request pointed at the rel="next" page
+func nextPage(request *http.Request, response *http.Response) (*http.Request, bool) {
+	for _, link := range strings.Split(response.Header.Get("Link"), ",") {
+		target, params, found := strings.Cut(link, ";")
+		if !found || !strings.Contains(params, `rel="next"`) {
+			continue
+		}
+
+		nextURL, err := url.Parse(strings.Trim(strings.TrimSpace(target), "<>"))
+		if err != nil {
+			return nil, false
+		}
+
+		next := request.Clone(request.Context())
+		next.URL = nextURL
+		next.Host = nextURL.Host
+		return next, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,135 @@
+package git
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func testClient(slept *[]time.Duration) *APIClient {
+	client := NewAPIClient()
+	client.sleep = func(wait time.Duration) {
+		*slept = append(*slept, wait)
+	}
+	return client
+}
+
+func TestClientRetriesIdempotentRequests(t *testing.T) {
+	t.Log("Testing the API client retries a GET after a server error")
+
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	var slept []time.Duration
+	request, _ := http.NewRequest("GET", server.URL, nil)
+	_, body, err := testClient(&slept).Do(request)
+	if err != nil {
+		t.Fatalf("Expected the request to succeed after retrying: %v", err)
+	}
+
+	if calls != 3 || string(body) != "[]" || len(slept) != 2 || slept[1] <= slept[0] {
+		t.Errorf("Expected 3 calls with an increasing backoff, got %d calls sleeping %v", calls, slept)
+	}
+}
+
+func TestClientDoesNotRetryPost(t *testing.T) {
+	t.Log("Testing the API client returns the message from a failed POST without retrying")
+
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"message": "Service unavailable"}`))
+	}))
+	defer server.Close()
+
+	var slept []time.Duration
+	request, _ := http.NewRequest("POST", server.URL, nil)
+	_, _, err := testClient(&slept).Do(request)
+
+	var apiError *APIError
+	if !errors.As(err, &apiError) {
+		t.Fatalf("Expected an APIError, got %v", err)
+	}
+
+	if calls != 1 || apiError.StatusCode != http.StatusServiceUnavailable || apiError.Message != "Service unavailable" {
+		t.Errorf("Unexpected result: %d calls, %+v", calls, apiError)
+	}
+}
+
+func TestClientWaitsOnRateLimit(t *testing.T) {
+	t.Log("Testing the API client waits for Retry-After on a secondary rate limit")
+
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message": "You have exceeded a secondary rate limit"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	var slept []time.Duration
+	request, _ := http.NewRequest("POST", server.URL, nil)
+	response, _, err := testClient(&slept).Do(request)
+	if err != nil {
+		t.Fatalf("Expected the request to succeed after waiting: %v", err)
+	}
+
+	if response.StatusCode != http.StatusCreated || len(slept) != 1 || slept[0] != 7*time.Second {
+		t.Errorf("Expected one 7s wait before a 201, slept %v and got %d", slept, response.StatusCode)
+	}
+}
+
+func TestCachedGetUsesETag(t *testing.T) {
+	t.Log("Testing a cached GET sends If-None-Match and answers a 304 from the cache")
+	t.Setenv("THOTH_CACHE_DIR", t.TempDir())
+
+	var conditional int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			conditional++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`[{"number":1}]`))
+	}))
+	defer server.Close()
+
+	credentials := Credentials{Host: "github.com", Owner: "jonathon-chew", Repo: "Thoth"}
+
+	for range 2 {
+		request, _ := http.NewRequest("GET", server.URL+"/issues", nil)
+		_, body, err := cachedGet(credentials, request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(body) != `[{"number":1}]` {
+			t.Errorf("Unexpected body %s", body)
+		}
+	}
+
+	if conditional != 1 {
+		t.Errorf("Expected the second request to be conditional, got %d conditional requests", conditional)
+	}
+
+	offlineBody, _, err := offlineGet(credentials, server.URL+"/issues")
+	if err != nil || string(offlineBody) != `[{"number":1}]` {
+		t.Errorf("Expected the offline read to return the cached body, got %s %v", offlineBody, err)
+	}
+}
@@ -0,0 +1,268 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// LineRange is the lines From to To, both included
+type LineRange struct {
+	From int
+	To   int
+}
+
+// ChangedLines maps each changed file (relative to the current directory, with / separators) to the lines added or changed in it
+type ChangedLines map[string][]LineRange
+
+// Contains is true when the line of the file was added or changed
+func (changed ChangedLines) Contains(path string, line int) bool {
+	for _, lineRange := range changed[filepath.ToSlash(filepath.Clean(path))] {
+		if line >= lineRange.From && line <= lineRange.To {
+			return true
+		}
+	}
+	return false
+}
+
+// HasFile is true when anything was added or changed in the file
+func (changed ChangedLines) HasFile(path string) bool {
+	return len(changed[filepath.ToSlash(filepath.Clean(path))]) > 0
+}
+
+// DiffChangedLines finds the lines added or changed in the staged changes, or since the ref when staged is false
+// The ref is compared from where it meets HEAD, so commits made on the ref since don't count as changes here
+func DiffChangedLines(since string, staged bool) (ChangedLines, error) {
+	arguments := []string{"diff", "--unified=0", "--no-color", "--no-ext-diff", "--relative"}
+
+	if staged {
+		arguments = append(arguments, "--cached")
+	} else {
+		mergeBase, err := runGit("merge-base", since, "HEAD")
+		if err != nil {
+			return nil, fmt.Errorf("unable to compare with %s: %w", since, err)
+		}
+		arguments = append(arguments, strings.TrimSpace(mergeBase))
+	}
+
+	diff, err := runGit(arguments...)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseUnifiedDiff(diff), nil
+}
+
+// PushChangedLines finds the lines a pre-push hook is about to send: from the remote's commit to the local one, or from where
+// the local commit meets origin's default branch when the remote branch is new. Nothing is returned when neither is known
+func PushChangedLines(localCommit, remoteCommit string) (ChangedLines, error) {
+	if strings.Trim(remoteCommit, "0") == "" {
+		mergeBase, err := runGit("merge-base", localCommit, "origin/HEAD")
+		if err != nil {
+			return ChangedLines{}, nil
+		}
+		remoteCommit = strings.TrimSpace(mergeBase)
+	}
+
+	diff, err := runGit("diff", "--unified=0", "--no-color", "--no-ext-diff", "--relative", remoteCommit, localCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseUnifiedDiff(diff), nil
+}
+
+// FileAt reads the file as it is in the revision, an empty revision is the staged version
+// The path is relative to the current directory like the ChangedLines paths
+func FileAt(revision, path string) (string, error) {
+	return runGit("show", revision+":./"+filepath.ToSlash(path))
+}
+
+// LineMap follows the lines of the staged version of a file to where they are in the working tree, see UnstagedLineMap
+type LineMap []lineHunk
+
+// A hunk of the diff from the staged file to the working tree: count lines from From were replaced with added lines
+type lineHunk struct {
+	from  int
+	count int
+	added int
+}
+
+// UnstagedLineMap compares the staged version of the file with the working tree, the path is relative to the current directory
+func UnstagedLineMap(path string) (LineMap, error) {
+	diff, err := runGit("diff", "--unified=0", "--no-color", "--no-ext-diff", "--", filepath.ToSlash(path))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseLineMap(diff), nil
+}
+
+// WorkingLine is where the staged line is in the working tree, false when the line was changed or removed since it was staged
+func (lineMap LineMap) WorkingLine(staged int) (int, bool) {
+	line := staged
+	for _, hunk := range lineMap {
+		// A hunk that removes nothing adds its lines after From
+		last := hunk.from
+		if hunk.count > 0 {
+			last = hunk.from + hunk.count - 1
+			if staged >= hunk.from && staged <= last {
+				return 0, false
+			}
+		}
+
+		if staged > last {
+			line += hunk.added - hunk.count
+		}
+	}
+	return line, true
+}
+
+// Reads both sides of each @@ -a,b +c,d @@ hunk header of a diff of one file
+func parseLineMap(diff string) LineMap {
+	var lineMap LineMap
+
+	for _, line := range strings.Split(diff, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[0] != "@@" {
+			continue
+		}
+
+		from, count, okOld := hunkRange(strings.TrimPrefix(fields[1], "-"))
+		_, added, okNew := hunkRange(strings.TrimPrefix(fields[2], "+"))
+		if okOld && okNew {
+			lineMap = append(lineMap, lineHunk{from: from, count: count, added: added})
+		}
+	}
+
+	return lineMap
+}
+
+// a,b or just a, which is one line
+func hunkRange(field string) (int, int, bool) {
+	start, count, hasCount := strings.Cut(field, ",")
+
+	from, err := strconv.Atoi(start)
+	if err != nil {
+		return 0, 0, false
+	}
+	if !hasCount {
+		return from, 1, true
+	}
+
+	lines, err := strconv.Atoi(count)
+	if err != nil {
+		return 0, 0, false
+	}
+	return from, lines, true
+}
+
+// HooksDirectory is where git looks for hooks, following core.hooksPath when it is set
+func HooksDirectory() (string, error) {
+	directory, err := runGit("rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(strings.TrimSpace(directory))
+}
+
+// WriteStaged replaces the staged version of the file with the contents and leaves the working tree alone, so only the lines
+// changed in contents are staged. The path is relative to the current directory and must already be staged
+func WriteStaged(path, contents string) error {
+	path = filepath.ToSlash(path)
+
+	entry, err := runGit("ls-files", "--stage", "--", path)
+	if err != nil {
+		return err
+	}
+	fields := strings.Fields(entry)
+	if len(fields) < 2 {
+		return fmt.Errorf("%s isn't staged", path)
+	}
+
+	// The staged version has already been through any clean filters, so store it as it is
+	hash, err := runGitWithInput(contents, "hash-object", "-w", "--stdin", "--no-filters")
+	if err != nil {
+		return err
+	}
+
+	// --cacheinfo takes the path from the top of the repository
+	prefix, err := runGit("rev-parse", "--show-prefix")
+	if err != nil {
+		return err
+	}
+
+	_, err = runGit("update-index", "--cacheinfo", fields[0]+","+strings.TrimSpace(hash)+","+strings.TrimSpace(prefix)+path)
+	return err
+}
+
+// runGit returns the output, with git's own message as the error when it fails
+func runGit(arguments ...string) (string, error) {
+	return runGitWithInput("", arguments...)
+}
+
+// runGitWithInput is runGit with the input given to git on stdin
+func runGitWithInput(input string, arguments ...string) (string, error) {
+	var stderr bytes.Buffer
+
+	command := exec.Command("git", arguments...)
+	command.Stdin = strings.NewReader(input)
+	command.Stderr = &stderr
+
+	output, err := command.Output()
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("git %s: %s", arguments[0], message)
+		}
+		return "", fmt.Errorf("git %s: %w", arguments[0], err)
+	}
+
+	return string(output), nil
+}
+
+// Reads the +++ file headers and the new side of each @@ -a,b +c,d @@ hunk header, hunks that only remove lines are skipped
+func parseUnifiedDiff(diff string) ChangedLines {
+	changed := ChangedLines{}
+
+	var path string
+	for _, line := range strings.Split(diff, "\n") {
+		if after, found := strings.CutPrefix(line, "+++ "); found {
+			path = ""
+			if after != "/dev/null" {
+				path = strings.TrimPrefix(after, "b/")
+			}
+			continue
+		}
+
+		if path == "" || !strings.HasPrefix(line, "@@ ") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+
+		start, count, _ := strings.Cut(strings.TrimPrefix(fields[2], "+"), ",")
+		from, err := strconv.Atoi(start)
+		if err != nil {
+			continue
+		}
+
+		lines := 1
+		if count != "" {
+			if lines, err = strconv.Atoi(count); err != nil {
+				continue
+			}
+		}
+
+		if lines > 0 {
+			changed[path] = append(changed[path], LineRange{From: from, To: from + lines - 1})
+		}
+	}
+
+	return changed
+}
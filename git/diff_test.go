@@ -0,0 +1,162 @@
+package git
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseUnifiedDiff(t *testing.T) {
+	diff := `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -10,0 +11,2 @@ func main() {
++	// TODO: one
++	// TODO: two
+@@ -40 +42 @@ func other() {
+-	old
++	new
+@@ -50,3 +51,0 @@ func gone() {
+-	a
+-	b
+-	c
+diff --git a/removed.go b/removed.go
+deleted file mode 100644
+--- a/removed.go
++++ /dev/null
+@@ -1,2 +0,0 @@
+-package removed
+-
+diff --git a/cmd/new.go b/cmd/new.go
+new file mode 100644
+--- /dev/null
++++ b/cmd/new.go
+@@ -0,0 +1,3 @@
++package cmd
++
++// TODO: three
+`
+
+	changed := parseUnifiedDiff(diff)
+
+	tests := []struct {
+		path string
+		line int
+		want bool
+	}{
+		{"main.go", 11, true},
+		{"main.go", 12, true},
+		{"main.go", 13, false},
+		{"main.go", 42, true},
+		{"main.go", 51, false},
+		{"removed.go", 1, false},
+		{"cmd/new.go", 3, true},
+		{"./cmd/new.go", 1, true},
+		{"other.go", 1, false},
+	}
+
+	for _, test := range tests {
+		if got := changed.Contains(test.path, test.line); got != test.want {
+			t.Errorf("Contains(%s, %d) = %v, want %v", test.path, test.line, got, test.want)
+		}
+	}
+
+	if changed.HasFile("removed.go") {
+		t.Error("a deleted file has no changed lines")
+	}
+}
+
+func TestLineMap(t *testing.T) {
+	// The staged file against the working tree: two lines added at the top, line 5 changed, lines 8 and 9 removed
+	diff := `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -0,0 +1,2 @@
++// new
++// new
+@@ -5 +7 @@ func main() {
+-	old
++	new
+@@ -8,2 +9,0 @@ func main() {
+-	a
+-	b
+`
+
+	lineMap := parseLineMap(diff)
+
+	tests := []struct {
+		staged  int
+		working int
+		found   bool
+	}{
+		{1, 3, true},
+		{4, 6, true},
+		{5, 0, false},
+		{6, 8, true},
+		{7, 9, true},
+		{8, 0, false},
+		{9, 0, false},
+		{10, 10, true},
+	}
+
+	for _, test := range tests {
+		working, found := lineMap.WorkingLine(test.staged)
+		if working != test.working || found != test.found {
+			t.Errorf("WorkingLine(%d) = %d, %v, want %d, %v", test.staged, working, found, test.working, test.found)
+		}
+	}
+
+	if working, found := LineMap(nil).WorkingLine(4); working != 4 || !found {
+		t.Error("a file without unstaged changes should keep its lines")
+	}
+}
+
+func TestWriteStaged(t *testing.T) {
+	root := t.TempDir()
+	t.Chdir(root)
+
+	run := func(arguments ...string) string {
+		output, err := runGit(arguments...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return output
+	}
+
+	run("init", "--quiet")
+	if err := os.MkdirAll("sub", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("sub/a.go", []byte("package a\n// TODO: staged\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run("add", "sub/a.go")
+
+	// A change that is held back from the commit
+	working := "package a\n// held back\n// TODO: staged\n"
+	if err := os.WriteFile("sub/a.go", []byte(working), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Chdir("sub")
+	if err := WriteStaged("a.go", "package a\n// (#3) TODO: staged\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	if staged, _ := FileAt("", "a.go"); staged != "package a\n// (#3) TODO: staged\n" {
+		t.Errorf("the staged file is %q", staged)
+	}
+	if contents, _ := os.ReadFile("a.go"); string(contents) != working {
+		t.Errorf("the working tree was changed to %q", contents)
+	}
+
+	if diff := run("diff", "--", "a.go"); !strings.Contains(diff, "+// held back") {
+		t.Errorf("the held back line should still be unstaged, the diff is %q", diff)
+	}
+
+	if err := WriteStaged("missing.go", "package a\n"); err == nil {
+		t.Error("a file that isn't staged should be an error")
+	}
+}
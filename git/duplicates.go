@@ -0,0 +1,148 @@
+package git
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// DuplicateMatch is an existing issue with a title close to the one about to be made, Score runs from 0 to 1
+type DuplicateMatch struct {
+	Issue GithubIssueResponse
+	Score float64
+}
+
+// The most issues of each state fetched to compare against, enough for any repository where the titles are read by people
+const duplicateCandidateLimit = 500
+
+// DuplicateCandidates fetches the open issues and the issues closed in the last closedWithin, the ones a new issue could be repeating
+func DuplicateCandidates(credentials Credentials, closedWithin time.Duration) ([]GithubIssueResponse, error) {
+	open, err := SearchIssues(credentials, IssueQuery{State: "open", Sort: "updated", Order: "desc", Limit: duplicateCandidateLimit})
+	if err != nil && !errors.Is(err, ErrNoIssues) {
+		return nil, err
+	}
+
+	if closedWithin <= 0 {
+		return open, nil
+	}
+
+	closed, err := SearchIssues(credentials, IssueQuery{
+		State:   "closed",
+		Updated: DateRange{From: time.Now().Add(-closedWithin)},
+		Sort:    "updated",
+		Order:   "desc",
+		Limit:   duplicateCandidateLimit,
+	})
+	if err != nil && !errors.Is(err, ErrNoIssues) {
+		return open, err
+	}
+
+	return append(open, closed...), nil
+}
+
+// FindDuplicates scores every candidate against the title and returns those at or above the threshold, best first
+func FindDuplicates(title string, candidates []GithubIssueResponse, threshold float64) []DuplicateMatch {
+	var matches []DuplicateMatch
+
+	for _, candidate := range candidates {
+		if score := TitleSimilarity(title, candidate.Title); score >= threshold {
+			matches = append(matches, DuplicateMatch{Issue: candidate, Score: score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+
+	return matches
+}
+
+// TitleSimilarity compares two titles once the issue number, TODO keyword, comment markers, case and punctuation are stripped
+// It is the higher of the edit distance ratio (catches typos) and the word overlap (catches reordering)
+func TitleSimilarity(a, b string) float64 {
+	wordsA, wordsB := titleWords(a), titleWords(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return 0
+	}
+
+	return max(levenshteinRatio(strings.Join(wordsA, " "), strings.Join(wordsB, " ")), wordOverlap(wordsA, wordsB))
+}
+
+// Words that only say the line is a TODO, not what it is about
+var titleNoiseWords = map[string]bool{"todo": true, "fixme": true, "hack": true, "xxx": true}
+
+func titleWords(title string) []string {
+	var words []string
+
+	fields := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for index, word := range fields {
+		// The (#12) in front of a numbered TODO is always the first word
+		if index == 0 && strings.Contains(title, "(#"+word+")") {
+			continue
+		}
+		if titleNoiseWords[word] {
+			continue
+		}
+		words = append(words, word)
+	}
+
+	return words
+}
+
+// 1 minus the edit distance over the length of the longer string
+func levenshteinRatio(a, b string) float64 {
+	runesA, runesB := []rune(a), []rune(b)
+	longest := max(len(runesA), len(runesB))
+	if longest == 0 {
+		return 1
+	}
+
+	previous := make([]int, len(runesB)+1)
+	current := make([]int, len(runesB)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(runesA); i++ {
+		current[0] = i
+		for j := 1; j <= len(runesB); j++ {
+			cost := 1
+			if runesA[i-1] == runesB[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return 1 - float64(previous[len(runesB)])/float64(longest)
+}
+
+// The shared words over all the words in either title (Jaccard)
+func wordOverlap(a, b []string) float64 {
+	setA := map[string]bool{}
+	for _, word := range a {
+		setA[word] = true
+	}
+
+	union := len(setA)
+	var shared int
+	seen := map[string]bool{}
+	for _, word := range b {
+		if seen[word] {
+			continue
+		}
+		seen[word] = true
+
+		if setA[word] {
+			shared++
+		} else {
+			union++
+		}
+	}
+
+	return float64(shared) / float64(union)
+}
@@ -0,0 +1,38 @@
+package git
+
+import "testing"
+
+func TestTitleSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b      string
+		duplicate bool
+	}{
+		{"// TODO: handle empty config file", "Handle empty config file", true},
+		{"(#12) TODO: handle empty config file", "// TODO: handle empty config file", true},
+		{"TODO: hadnle empty config file", "handle empty config file", true},
+		{"TODO: config file empty handle", "handle empty config file", true},
+		{"TODO: handle empty config file", "Retry requests on a 502", false},
+		{"TODO: add tests", "TODO: add docs", false},
+		{"TODO:", "TODO:", false},
+	}
+
+	for _, test := range tests {
+		score := TitleSimilarity(test.a, test.b)
+		if (score >= 0.85) != test.duplicate {
+			t.Errorf("TitleSimilarity(%q, %q) = %.2f, want duplicate %v", test.a, test.b, score, test.duplicate)
+		}
+	}
+}
+
+func TestFindDuplicatesBestFirst(t *testing.T) {
+	candidates := []GithubIssueResponse{
+		{Number: 1, Title: "Handle an empty config"},
+		{Number: 2, Title: "Handle empty config file"},
+		{Number: 3, Title: "Something else entirely"},
+	}
+
+	matches := FindDuplicates("TODO: handle empty config file", candidates, 0.6)
+	if len(matches) != 2 || matches[0].Issue.Number != 2 || matches[1].Issue.Number != 1 {
+		t.Fatalf("unexpected matches %+v", matches)
+	}
+}
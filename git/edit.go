@@ -0,0 +1,273 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// IssueChanges describes an edit, anything left empty (or nil for Title / Body) is not changed
+type IssueChanges struct {
+	Title           *string
+	Body            *string
+	State           string // open or closed
+	StateReason     string // completed, not_planned or reopened - GitHub only
+	AddLabels       []string
+	RemoveLabels    []string
+	AddAssignees    []string
+	RemoveAssignees []string
+}
+
+// The fields GitHub's PATCH /issues/{number} accepts, omitted fields are left alone
+type Github_Issue_Update struct {
+	Title        *string  `json:"title,omitempty"`
+	Body         *string  `json:"body,omitempty"`
+	State        string   `json:"state,omitempty"`
+	State_reason string   `json:"state_reason,omitempty"`
+	Labels       []string `json:"labels,omitempty"`
+	Assignees    []string `json:"assignees,omitempty"`
+}
+
+// The fields GitLab's PUT /projects/{id}/issues/{iid} accepts, labels are comma separated
+type Gitlab_Issue_Update struct {
+	Title         *string `json:"title,omitempty"`
+	Description   *string `json:"description,omitempty"`
+	State_event   string  `json:"state_event,omitempty"`
+	Add_labels    string  `json:"add_labels,omitempty"`
+	Remove_labels string  `json:"remove_labels,omitempty"`
+	Assignee_ids  []int   `json:"assignee_ids,omitempty"`
+}
+
+// EditIssue applies the changes and returns the issue as it is afterwards
+func EditIssue(credentials Credentials, number int, changes IssueChanges) (GithubIssueResponse, error) {
+	if isGitlabHost(credentials.Host) {
+		return editGitlabIssue(credentials, number, changes)
+	}
+	return editGithubIssue(credentials, number, changes)
+}
+
+func editGithubIssue(credentials Credentials, number int, changes IssueChanges) (GithubIssueResponse, error) {
+	var issue GithubIssueResponse
+
+	update := Github_Issue_Update{
+		Title:        changes.Title,
+		Body:         changes.Body,
+		State:        changes.State,
+		State_reason: changes.StateReason,
+	}
+
+	// The API replaces the whole list so work out the new lists from what's there now
+	if len(changes.AddLabels)+len(changes.RemoveLabels)+len(changes.AddAssignees)+len(changes.RemoveAssignees) > 0 {
+		current, err := GetIssue(credentials, number)
+		if err != nil {
+			return issue, err
+		}
+
+		var labels, assignees []string
+		for _, label := range current.Labels {
+			labels = append(labels, label.Name)
+		}
+		for _, assignee := range current.Assignees {
+			assignees = append(assignees, assignee.Login)
+		}
+
+		if len(changes.AddLabels)+len(changes.RemoveLabels) > 0 {
+			update.Labels = applyListChanges(labels, changes.AddLabels, changes.RemoveLabels)
+		}
+		if len(changes.AddAssignees)+len(changes.RemoveAssignees) > 0 {
+			update.Assignees = applyListChanges(assignees, changes.AddAssignees, changes.RemoveAssignees)
+		}
+	}
+
+	payload, err := githubUpdatePayload(update)
+	if err != nil {
+		return issue, err
+	}
+
+	request, err := newGithubRequest(credentials, "PATCH", fmt.Sprintf("/repos/%s/%s/issues/%d", credentials.Owner, credentials.Repo, number), payload)
+	if err != nil {
+		return issue, err
+	}
+
+	_, responseBody, err := Client.Do(request)
+	if err != nil {
+		return issue, err
+	}
+
+	if err := json.Unmarshal(responseBody, &issue); err != nil {
+		return issue, fmt.Errorf("error unmarshalling response: %w", err)
+	}
+
+	return issue, nil
+}
+
+// omitempty drops empty lists, but an empty list is how every label or assignee is removed, so add them back in by hand
+func githubUpdatePayload(update Github_Issue_Update) (map[string]any, error) {
+	jsonData, err := json.Marshal(update)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := map[string]any{}
+	if err := json.Unmarshal(jsonData, &payload); err != nil {
+		return nil, err
+	}
+
+	if update.Labels != nil {
+		payload["labels"] = update.Labels
+	}
+	if update.Assignees != nil {
+		payload["assignees"] = update.Assignees
+	}
+
+	return payload, nil
+}
+
+func editGitlabIssue(credentials Credentials, number int, changes IssueChanges) (GithubIssueResponse, error) {
+	var issue GithubIssueResponse
+
+	update := Gitlab_Issue_Update{
+		Title:         changes.Title,
+		Description:   changes.Body,
+		Add_labels:    strings.Join(changes.AddLabels, ","),
+		Remove_labels: strings.Join(changes.RemoveLabels, ","),
+	}
+
+	switch changes.State {
+	case "closed":
+		update.State_event = "close"
+	case "open":
+		update.State_event = "reopen"
+	}
+
+	// GitLab assigns by user id rather than username
+	if len(changes.AddAssignees)+len(changes.RemoveAssignees) > 0 {
+		current, err := getGitlabIssue(credentials, number)
+		if err != nil {
+			return issue, err
+		}
+
+		var usernames []string
+		for _, assignee := range current.Assignees {
+			usernames = append(usernames, assignee.Username)
+		}
+
+		for _, username := range applyListChanges(usernames, changes.AddAssignees, changes.RemoveAssignees) {
+			id, err := gitlabUserID(credentials, username)
+			if err != nil {
+				return issue, err
+			}
+			update.Assignee_ids = append(update.Assignee_ids, id)
+		}
+
+		// GitLab unassigns everyone when sent 0
+		if len(update.Assignee_ids) == 0 {
+			update.Assignee_ids = []int{0}
+		}
+	}
+
+	request, err := newGitlabRequest(credentials, "PUT", fmt.Sprintf("%s/issues/%d", gitlabProjectPath(credentials), number), update)
+	if err != nil {
+		return issue, err
+	}
+
+	_, responseBody, err := Client.Do(request)
+	if err != nil {
+		return issue, err
+	}
+
+	var gitlabIssue Get_Gitlab_Issue_Response
+	if err := json.Unmarshal(responseBody, &gitlabIssue); err != nil {
+		return issue, fmt.Errorf("error unmarshalling response: %w", err)
+	}
+
+	return gitlabIssue.GithubIssue(), nil
+}
+
+func gitlabUserID(credentials Credentials, username string) (int, error) {
+	request, err := newGitlabRequest(credentials, "GET", "/users?username="+url.QueryEscape(username), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	_, responseBody, err := Client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+
+	var users []Gitlab_Author
+	if err := json.Unmarshal(responseBody, &users); err != nil {
+		return 0, fmt.Errorf("error unmarshalling response: %w", err)
+	}
+
+	if len(users) == 0 {
+		return 0, fmt.Errorf("there is no GitLab user called %s", username)
+	}
+
+	return users[0].Id, nil
+}
+
+// Remove then add, keeping the original order and not adding anything twice
+// Removing everything gives an empty list rather than nil, as that is how GitHub is told to clear the list
+func applyListChanges(current, add, remove []string) []string {
+	result := []string{}
+	for _, item := range current {
+		if !slices.Contains(remove, item) {
+			result = append(result, item)
+		}
+	}
+
+	for _, item := range add {
+		if !slices.Contains(result, item) {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+// CommentOnIssue adds a comment (a note on GitLab) to the issue
+func CommentOnIssue(credentials Credentials, number int, body string) (Github_Comment, error) {
+	var comment Github_Comment
+
+	payload := map[string]string{"body": body}
+
+	if isGitlabHost(credentials.Host) {
+		request, err := newGitlabRequest(credentials, "POST", fmt.Sprintf("%s/issues/%d/notes", gitlabProjectPath(credentials), number), payload)
+		if err != nil {
+			return comment, err
+		}
+
+		_, responseBody, err := Client.Do(request)
+		if err != nil {
+			return comment, err
+		}
+
+		var note Gitlab_Note
+		if err := json.Unmarshal(responseBody, &note); err != nil {
+			return comment, fmt.Errorf("error unmarshalling response: %w", err)
+		}
+
+		comment = Github_Comment{Id: note.Id, Body: note.Body, Created_at: note.Created_at, Updated_at: note.Updated_at}
+		comment.User.Login = note.Author.Username
+		return comment, nil
+	}
+
+	request, err := newGithubRequest(credentials, "POST", fmt.Sprintf("/repos/%s/%s/issues/%d/comments", credentials.Owner, credentials.Repo, number), payload)
+	if err != nil {
+		return comment, err
+	}
+
+	_, responseBody, err := Client.Do(request)
+	if err != nil {
+		return comment, err
+	}
+
+	if err := json.Unmarshal(responseBody, &comment); err != nil {
+		return comment, fmt.Errorf("error unmarshalling response: %w", err)
+	}
+
+	return comment, nil
+}
@@ -0,0 +1,61 @@
+package git
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestApplyListChanges(t *testing.T) {
+	tests := []struct {
+		current, add, remove []string
+		want                 string
+	}{
+		{[]string{"bug", "p1"}, []string{"docs"}, nil, "bug,p1,docs"},
+		{[]string{"bug", "p1"}, nil, []string{"bug"}, "p1"},
+		{[]string{"bug"}, []string{"bug", "p2", "p2"}, nil, "bug,p2"},
+		{[]string{"bug", "p1"}, []string{"p3"}, []string{"p1", "missing"}, "bug,p3"},
+		{[]string{"bug"}, []string{"bug"}, []string{"bug"}, "bug"},
+		{nil, []string{"sam"}, nil, "sam"},
+		{[]string{"bug", "p1"}, nil, []string{"bug", "p1"}, ""},
+	}
+
+	for _, test := range tests {
+		got := applyListChanges(test.current, test.add, test.remove)
+		if strings.Join(got, ",") != test.want {
+			t.Errorf("applyListChanges(%v, %v, %v) = %v, want %s", test.current, test.add, test.remove, got, test.want)
+		}
+		if got == nil {
+			t.Errorf("applyListChanges(%v, %v, %v) is nil, so the change would be left out of the update", test.current, test.add, test.remove)
+		}
+	}
+}
+
+func TestGithubUpdatePayload(t *testing.T) {
+	title := "New title"
+
+	tests := []struct {
+		name   string
+		update Github_Issue_Update
+		want   string
+	}{
+		{"only what changed", Github_Issue_Update{Title: &title, State: "closed", State_reason: "not_planned"}, `{"state":"closed","state_reason":"not_planned","title":"New title"}`},
+		{"lists left alone", Github_Issue_Update{State: "open"}, `{"state":"open"}`},
+		{"new lists", Github_Issue_Update{Labels: []string{"bug"}, Assignees: []string{"sam"}}, `{"assignees":["sam"],"labels":["bug"]}`},
+		{"cleared labels", Github_Issue_Update{Labels: []string{}}, `{"labels":[]}`},
+		{"cleared assignees", Github_Issue_Update{Assignees: applyListChanges([]string{"sam"}, nil, []string{"sam"})}, `{"assignees":[]}`},
+	}
+
+	for _, test := range tests {
+		payload, err := githubUpdatePayload(test.update)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Maps marshal with sorted keys so the JSON can be compared as a string
+		got, _ := json.Marshal(payload)
+		if string(got) != test.want {
+			t.Errorf("%s: payload is %s, want %s", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+package git
+
+import "errors"
+
+// Sentinel errors so callers can branch with errors.Is, API failures come back as *APIError (see client.go)
+var (
+	ErrNoIssues = errors.New("no issues found")
+	ErrNoRemote = errors.New("unable to get the remote origin")
+	ErrNoToken  = errors.New("no token found")
+)
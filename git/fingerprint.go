@@ -0,0 +1,121 @@
+package git
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Fingerprint identifies a TODO apart from the (#N) on its line, so a TODO that lost its number, moved file or was lightly
+// reworded can be matched back to its issue. It is saved in the issue body as a hidden HTML comment
+type Fingerprint struct {
+	ID    string   `json:"id"`    // Hash of Text, only the same TODO when the paths overlap too
+	Text  string   `json:"text"`  // The TODO with the number, keyword, comment markers, case and punctuation removed
+	Paths []string `json:"paths"` // Every path the file has had, newest first
+}
+
+// How alike the text needs to be when the TODO is in a file the issue knows about
+const fingerprintTextThreshold = 0.7
+
+var fingerprintComment = regexp.MustCompile(`<!-- thoth:fingerprint (\{.*?\}) -->`)
+
+// NewFingerprint normalises the TODO text and keeps the paths given, see PathHistory
+func NewFingerprint(todo string, paths []string) Fingerprint {
+	text := strings.Join(titleWords(todo), " ")
+	sum := sha256.Sum256([]byte(text))
+
+	return Fingerprint{ID: hex.EncodeToString(sum[:])[:12], Text: text, Paths: paths}
+}
+
+// PathHistory is the path followed by every name the file had before, from git log --follow
+// Files git doesn't know about yet only have their current path
+func PathHistory(path string) []string {
+	path = filepath.ToSlash(filepath.Clean(path))
+	paths := []string{path}
+
+	output, err := exec.Command("git", "log", "--follow", "--name-only", "--format=", "--", path).Output()
+	if err != nil {
+		return paths
+	}
+
+	// git log gives paths from the repository root, so make them relative to here like the path passed in
+	prefix, err := exec.Command("git", "rev-parse", "--show-prefix").Output()
+	if err != nil {
+		return paths
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		line = strings.TrimPrefix(line, strings.TrimSpace(string(prefix)))
+
+		if !slices.Contains(paths, line) {
+			paths = append(paths, line)
+		}
+	}
+
+	return paths
+}
+
+// Comment is the hidden HTML comment added to the issue body, json escapes < and > so the comment can't be closed early
+func (fingerprint Fingerprint) Comment() string {
+	jsonData, _ := json.Marshal(fingerprint)
+	return "<!-- thoth:fingerprint " + string(jsonData) + " -->"
+}
+
+// ParseFingerprint finds the fingerprint comment in an issue body
+func ParseFingerprint(body string) (Fingerprint, bool) {
+	var fingerprint Fingerprint
+
+	match := fingerprintComment.FindStringSubmatch(body)
+	if match == nil {
+		return fingerprint, false
+	}
+
+	if err := json.Unmarshal([]byte(match[1]), &fingerprint); err != nil {
+		return fingerprint, false
+	}
+
+	return fingerprint, fingerprint.ID != ""
+}
+
+// FindByFingerprint returns the candidate the TODO belongs to: the same or close enough text in a file that has been at
+// one of the same paths. The same text in an unrelated file is a different TODO, "handle error" is written in many places.
+// The same text wins, otherwise the closest text when several qualify
+func FindByFingerprint(fingerprint Fingerprint, candidates []GithubIssueResponse) (GithubIssueResponse, bool) {
+	var best GithubIssueResponse
+	var bestScore float64
+
+	for _, candidate := range candidates {
+		existing, found := ParseFingerprint(candidate.Body)
+		if !found || !sharesPath(existing.Paths, fingerprint.Paths) {
+			continue
+		}
+
+		if existing.ID == fingerprint.ID {
+			return candidate, true
+		}
+
+		if score := TitleSimilarity(existing.Text, fingerprint.Text); score >= fingerprintTextThreshold && score > bestScore {
+			best, bestScore = candidate, score
+		}
+	}
+
+	return best, bestScore > 0
+}
+
+func sharesPath(a, b []string) bool {
+	for _, path := range a {
+		if slices.Contains(b, path) {
+			return true
+		}
+	}
+	return false
+}
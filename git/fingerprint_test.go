@@ -0,0 +1,46 @@
+package git
+
+import "testing"
+
+func TestFingerprintRoundTrip(t *testing.T) {
+	fingerprint := NewFingerprint("// TODO: handle --> in paths", []string{"cmd/new.go", "cmd/old.go"})
+
+	body := "This is from file new.go on line 3\n\n" + fingerprint.Comment() + "\n"
+	parsed, found := ParseFingerprint(body)
+	if !found {
+		t.Fatalf("no fingerprint found in %q", body)
+	}
+
+	if parsed.ID != fingerprint.ID || parsed.Text != "handle in paths" || len(parsed.Paths) != 2 {
+		t.Errorf("parsed %+v, want %+v", parsed, fingerprint)
+	}
+}
+
+func TestFindByFingerprint(t *testing.T) {
+	original := NewFingerprint("// TODO: retry the request when the API times out", []string{"client.go"})
+	candidates := []GithubIssueResponse{
+		{Number: 4, Body: "no fingerprint here"},
+		{Number: 7, Body: "This is from file client.go on line 12\n\n" + original.Comment()},
+	}
+
+	tests := []struct {
+		name   string
+		todo   string
+		paths  []string
+		number int
+	}{
+		{"number removed", "(#7) TODO: retry the request when the API times out", []string{"client.go"}, 7},
+		{"file renamed", "// TODO: retry the request when the API times out", []string{"http/client.go", "client.go"}, 7},
+		{"same text in another file", "// TODO: retry the request when the API times out", []string{"other.go"}, 0},
+		{"file renamed and reworded", "// TODO: retry the request if the API times out", []string{"http/client.go", "client.go"}, 7},
+		{"reworded in another file", "// TODO: retry the request if the API times out", []string{"server.go"}, 0},
+		{"different TODO in the same file", "// TODO: log the response headers", []string{"client.go"}, 0},
+	}
+
+	for _, test := range tests {
+		issue, found := FindByFingerprint(NewFingerprint(test.todo, test.paths), candidates)
+		if found != (test.number != 0) || issue.Number != test.number {
+			t.Errorf("%s: got #%d (found %v), want #%d", test.name, issue.Number, found, test.number)
+		}
+	}
+}
@@ -17,7 +17,9 @@ import (
 )
 
 var HTTPStatusResponseMeanings = map[string]string{
+	"200": "OK",
 	"201": "Created",
+	"304": "Not Modified, served from the cache",
 	"400": "Bad Request",
 	"401": "Unauthorized",
 	"403": "Forbidden",
@@ -27,10 +29,15 @@ var HTTPStatusResponseMeanings = map[string]string{
 	"503": "Service unavailable",
 }
 
+// CreatedIssue starts the line printed for each issue made, thoth scan --workspace counts issues by it
+const CreatedIssue = "Created issue #"
+
 type Credentials struct {
-	Owner string
-	Repo  string
-	Token string
+	Host   string
+	Owner  string
+	Repo   string
+	Token  string
+	Source string // Where the token was found e.g. GITHUB_TOKEN or gh config
 }
 
 // type CommitMap map[string]int
@@ -47,8 +54,10 @@ func GetRemoteOrigin() (string, error) {
 
 	err := cmd.Run()
 	if err != nil {
-		fmt.Printf("Error: %s\n", stderr.String())
-		return "", err
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("%w: %s", ErrNoRemote, message)
+		}
+		return "", ErrNoRemote
 	}
 
 	return out.String(), nil
@@ -304,58 +313,44 @@ func NewGitTag(argument string) error {
 	return nil
 }
 
-// MAKE A GIT REQUEST
-func GenericGitRequest() (Credentials, error) {
-	remoteOrigin, err := GetRemoteOrigin()
+// RemoteRepository reads the host, owner and repository from the remote origin without looking for a token
+func RemoteRepository() (Credentials, error) {
 	var credentials Credentials
+
+	remoteOrigin, err := GetRemoteOrigin()
 	if err != nil {
-		fmt.Printf("Unable to get the remote origin\n")
 		return credentials, err
 	}
 
-	if strings.Contains(remoteOrigin, "github") {
-
-		gitUrl := strings.ReplaceAll(remoteOrigin, ".git", "")
-		gitDetails := strings.Split(strings.ReplaceAll(gitUrl, "https://github.com/", ""), "/")
-
-		credentials.Owner = gitDetails[0]
-		credentials.Repo = strings.Replace(gitDetails[1], "\n", "", -1)
-		credentials.Token = os.Getenv("GH_PERSONAL_TOKEN")
-
-		if credentials.Token == "" {
-			_, VarExists := os.LookupEnv("GH_PERSONAL_TOKEN")
-			if VarExists {
-				return credentials, errors.New("GH_PERSONAL_TOKEN is empty")
-			} else {
-				return credentials, errors.New("no GH_PERSONAL_TOKEN in the environment")
-			}
-		}
-
-		return credentials, nil
-
-	} else if strings.Contains(remoteOrigin, "gitlab") {
+	host, owner, repo, err := parseRemoteURL(remoteOrigin)
+	if err != nil {
+		return credentials, err
+	}
 
-		gitUrl := strings.ReplaceAll(remoteOrigin, ".git", "")
-		gitDetails := strings.Split(strings.ReplaceAll(gitUrl, "https://gitlab.", ""), "/")
+	if !isGithubHost(host) && !isGitlabHost(host) {
+		return credentials, fmt.Errorf("the remote origin is not github/gitlab, and the ability to create issues for %s is not currently implimented", strings.TrimSpace(remoteOrigin))
+	}
 
-		credentials.Owner = gitDetails[0] // check this still applies for gitlab - as i'm not sure it does, this might need to be a git call
-		credentials.Repo = strings.Replace(gitDetails[1], "\n", "", -1)
-		credentials.Token = os.Getenv("GL_PERSONAL_TOKEN")
+	credentials.Host = host
+	credentials.Owner = owner
+	credentials.Repo = repo
 
-		if credentials.Token == "" {
-			_, VarExists := os.LookupEnv("GL_PERSONAL_TOKEN")
-			if VarExists {
-				return credentials, errors.New("GL_PERSONAL_TOKEN is empty")
-			} else {
-				return credentials, errors.New("no GL_PERSONAL_TOKEN in the environment")
-			}
-		}
+	return credentials, nil
+}
 
-		return credentials, nil
+// MAKE A GIT REQUEST
+func GenericGitRequest() (Credentials, error) {
+	credentials, err := RemoteRepository()
+	if err != nil {
+		return credentials, err
+	}
 
-	} else {
-		return credentials, fmt.Errorf("the remote origin is not github/gitlab, and the ability to create issues for %s is not currently implimented", remoteOrigin)
+	credentials.Token, credentials.Source, err = ResolveToken(credentials.Host)
+	if err != nil {
+		return credentials, err
 	}
+
+	return credentials, nil
 }
 
 // Entry is the folder that you would like to check if their is an update to git in it.
@@ -3,15 +3,13 @@ package git
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
 	"os"
 	"os/exec"
 	"strconv"
 	"strings"
+	"time"
 
 	utils "github.com/jonathon-chew/Thoth/Utils"
 )
@@ -27,10 +25,14 @@ type Github_Issue struct {
 	Body      string   `json:"body"`
 	Milestone int      `json:"milestone,omitempty"`
 	Label     []string `json:"labels,omitempty"`
-	Assignees string   `json:"assignees,omitempty"`
+	Assignees []string `json:"assignees,omitempty"`
+	DueDate   string   `json:"-"` // 2026-12-01, a milestone due that day on GitHub and the due_date on GitLab
 }
 
 type Github_Label struct {
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description"`
 }
 
 type GithubIssueResponse struct {
@@ -39,6 +41,7 @@ type GithubIssueResponse struct {
 	Labels_url     string `json:"labels_url"`
 	Comments_url   string `json:"comments_url"`
 	Events_url     string `json:"events_url"`
+	Html_url       string `json:"html_url"`
 	Id             int    `json:"id"`
 	Node_id        string `json:"node_id"`
 	Number         int    `json:"number"`
@@ -89,54 +92,59 @@ func ListGithubIssues(passedFromCLI bool) ([]GithubIssueResponse, error) {
 		return ResponseInstance, err
 	}
 
-	request, err := http.NewRequest("GET", fmt.Sprintf("https://api.github.com/repos/%s/%s/issues?state=all", GitCredentials.Owner, GitCredentials.Repo), nil)
+	request, err := newGithubRequest(GitCredentials, "GET", githubIssueListPath(GitCredentials), nil)
 	if err != nil {
 		return ResponseInstance, err
 	}
 
-	request.Header.Set("Accept", "application/vnd.github+json")
-	request.Header.Set("X-GitHub-Api-Version", "2022-11-28")
-	request.Header.Set("Authorization", fmt.Sprintf("token %s", GitCredentials.Token))
-
-	client := http.Client{}
-
-	req, err := client.Do(request)
+	// Conditional on the cached ETag so an unchanged list costs nothing from the rate limit
+	response, responseBody, err := cachedGet(GitCredentials, request)
+	if !passedFromCLI && response != nil {
+		fmt.Printf("The response was: %s, %s\n\n", response.Status, HTTPStatusResponseMeanings[strconv.Itoa(response.StatusCode)])
+	}
 	if err != nil {
 		return ResponseInstance, err
 	}
 
-	defer req.Body.Close()
+	if err := json.Unmarshal(responseBody, &ResponseInstance); err != nil {
+		return ResponseInstance, fmt.Errorf("error unmarshalling response: %w", err)
+	}
 
-	if !passedFromCLI {
-		fmt.Printf("The response was: %s, %s\n\n", req.Status, HTTPStatusResponseMeanings[req.Status])
+	if len(ResponseInstance) == 0 {
+		return ResponseInstance, ErrNoIssues
 	}
 
-	responseBody, err := io.ReadAll(req.Body)
+	return ResponseInstance, nil
+}
+
+// ListGithubIssuesOffline returns the issue list saved by the last ListGithubIssues call without using the network or a token
+func ListGithubIssuesOffline() ([]GithubIssueResponse, time.Time, error) {
+
+	var ResponseInstance []GithubIssueResponse
+
+	GitCredentials, err := RemoteRepository()
 	if err != nil {
-		return ResponseInstance, err
+		return ResponseInstance, time.Time{}, err
 	}
 
-	// fmt.Printf("Repsonse Body: %s\n\n", string(responseBody))
+	responseBody, savedAt, err := offlineGet(GitCredentials, githubAPIBase(GitCredentials.Host)+githubIssueListPath(GitCredentials))
+	if err != nil {
+		return ResponseInstance, savedAt, err
+	}
 
 	if err := json.Unmarshal(responseBody, &ResponseInstance); err != nil {
-		return ResponseInstance, fmt.Errorf("error unmarshalling response: %w", err)
+		return ResponseInstance, savedAt, fmt.Errorf("error unmarshalling cached response: %w", err)
 	}
 
 	if len(ResponseInstance) == 0 {
-		return ResponseInstance, errors.New("no GitHub issues found")
-	}
-
-	if req.StatusCode != http.StatusOK {
-		return ResponseInstance, fmt.Errorf("GitHub API error: %s", req.Status)
+		return ResponseInstance, savedAt, ErrNoIssues
 	}
 
-	// fmt.Printf("ResponseInstance: %v\n\n", ResponseInstance)
-
-	// for _, response := range ResponseInstance {
-	// 	fmt.Println("The title for the response is: ", strings.TrimSpace(response.Title), " with ID: ", response.Id)
-	// }
+	return ResponseInstance, savedAt, nil
+}
 
-	return ResponseInstance, nil
+func githubIssueListPath(credentials Credentials) string {
+	return fmt.Sprintf("/repos/%s/%s/issues?state=all", credentials.Owner, credentials.Repo)
 }
 
 func MakeGithubIssue(TITLE, BODY string) error {
@@ -153,44 +161,50 @@ func MakeGithubIssue(TITLE, BODY string) error {
 		Body:  BODY,
 	}
 
-	// Convert the struct into JSON using the tags and Marshal
-	jsonData, err := json.Marshal(issue)
+	createdIssue, err := CreateIssue(GithubCredentials, issue)
 	if err != nil {
 		return err
 	}
 
-	// Convert the JSON into bytes
-	requestBody := bytes.NewBuffer(jsonData)
+	fmt.Printf(CreatedIssue+"%d %s\n", createdIssue.Number, createdIssue.Html_url)
 
-	// Make the request
-	request, err := http.NewRequest("POST", fmt.Sprintf("https://api.github.com/repos/%s/%s/issues", GithubCredentials.Owner, GithubCredentials.Repo), io.Reader(requestBody))
-	if err != nil {
-		fmt.Printf("Error making the HTTP request %s\n", err)
-		return err
-	}
+	return nil
+}
 
-	// Set the required headers
-	request.Header.Set("Accept", "application/vnd.github+json")
-	request.Header.Set("X-GitHub-Api-Version", "2022-11-28")
-	request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", GithubCredentials.Token))
+// CreateIssue files a new issue (on GitHub or GitLab depending on the remote) and returns it as the tracker saved it
+func CreateIssue(credentials Credentials, issue Github_Issue) (GithubIssueResponse, error) {
+	var createdIssue GithubIssueResponse
 
-	// Make a new client
-	client := http.Client{}
+	if isGitlabHost(credentials.Host) {
+		return createGitlabIssue(credentials, issue)
+	}
 
-	// Complete the request - Client.Do because the http.NewRequest handles the method
-	req, err := client.Do(request)
+	// GitHub issues don't have a due date of their own so they go in a milestone due that day
+	if issue.DueDate != "" && issue.Milestone == 0 {
+		milestone, err := DueMilestone(credentials, issue.DueDate)
+		if err != nil {
+			return createdIssue, fmt.Errorf("unable to find a milestone due %s: %w", issue.DueDate, err)
+		}
+		issue.Milestone = milestone
+	}
+
+	// Make the request, the struct is converted into JSON using the tags
+	request, err := newGithubRequest(credentials, "POST", fmt.Sprintf("/repos/%s/%s/issues", credentials.Owner, credentials.Repo), issue)
 	if err != nil {
-		return err
+		return createdIssue, err
 	}
 
-	if req.StatusCode != 200 && req.StatusCode != 201 {
-		fmt.Println(req.Body)
-		return fmt.Errorf("the response was not positive, %d", req.StatusCode)
+	// Complete the request - the shared client handles retries and turns a bad status into an APIError
+	_, responseBody, err := Client.Do(request)
+	if err != nil {
+		return createdIssue, err
 	}
 
-	fmt.Printf("The response was: %s, %s\n", req.Status, HTTPStatusResponseMeanings[req.Status])
+	if err := json.Unmarshal(responseBody, &createdIssue); err != nil {
+		return createdIssue, fmt.Errorf("error unmarshalling response: %w", err)
+	}
 
-	return nil
+	return createdIssue, nil
 }
 
 // REMOVE GIT ISSUES
@@ -226,38 +240,14 @@ func CloseGithubIssue(closeIssue *GithubIssueResponse) error {
 		return err
 	}
 
-	// Convert the struct into JSON using the tags and Marshal
-	jsonData, err := json.Marshal(closeIssue)
+	// Only the state is changing so only send that rather than the whole response back
+	_, err = EditIssue(GithubCredentials, closeIssue.Number, IssueChanges{State: closeIssue.State, StateReason: closeIssue.State_Reason})
 	if err != nil {
 		return err
 	}
 
-	// Convert the JSON into bytes
-	requestBody := bytes.NewBuffer(jsonData)
-
-	// Write the request
-	request, err := http.NewRequest("PATCH", fmt.Sprintf("https://api.github.com/repos/%s/%s/issues/%d", GithubCredentials.Owner, GithubCredentials.Repo, closeIssue.Number), requestBody)
-	if err != nil {
-		return err
-	}
+	fmt.Printf("Closed issue #%d\n", closeIssue.Number)
 
-	// Set the required headers
-	request.Header.Set("Accept", "application/vnd.github+json")
-	request.Header.Set("X-GitHub-Api-Version", "2022-11-28")
-	request.Header.Set("Authorization", fmt.Sprintf("token %s", GithubCredentials.Token))
-
-	client := http.Client{}
-
-	// Make the request
-	closeGithubIssueResponse, clientErr := client.Do(request)
-	if clientErr != nil {
-		fmt.Printf("The response from github was: %s\n", HTTPStatusResponseMeanings[closeGithubIssueResponse.Status])
-		return clientErr
-	}
-
-	fmt.Printf("The response from github was: %s\n", HTTPStatusResponseMeanings[closeGithubIssueResponse.Status])
-
-	// Return if error?
 	return nil
 
 }
@@ -279,18 +269,20 @@ func CloneAllPublicRepos() {
 		return
 	}
 
-	var UserUrl string = "https://api.github.com/users/" + userName
-	userReq, err := http.Get(UserUrl)
+	// These are public endpoints so no token is sent
+	userReq, err := newGithubRequest(Credentials{}, "GET", "/users/"+userName, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	_, userBody, err := Client.Do(userReq)
 	if err != nil {
 		log.Fatal(err)
 	}
 	var userDetails User
-	if err := json.NewDecoder(userReq.Body).Decode(&userDetails); err != nil {
+	if err := json.Unmarshal(userBody, &userDetails); err != nil {
 		log.Fatalf("Error unmarshalling JSON: %v", err)
 	}
 
-	userReq.Body.Close()
-
 	if userDetails.Public_repos > 50 {
 		userReponse, ErrGettingConfirmLargeDownload := utils.GetUserInput([]byte("There are " + strconv.Itoa(userDetails.Public_repos) + " repos to clone - are you sure? y/Y\n"))
 		if ErrGettingConfirmLargeDownload != nil {
@@ -304,16 +296,17 @@ func CloneAllPublicRepos() {
 		}
 	}
 
-	var RepoURL string = "https://api.github.com/users/" + userName + "/repos"
-	repoReq, err := http.Get(RepoURL)
+	repoReq, err := newGithubRequest(Credentials{}, "GET", "/users/"+userName+"/repos", nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	_, repoBody, err := Client.Do(repoReq)
 	if err != nil {
 		log.Fatal(err)
 	}
-
-	defer repoReq.Body.Close()
 
 	var repos []Repo
-	if err := json.NewDecoder(repoReq.Body).Decode(&repos); err != nil {
+	if err := json.Unmarshal(repoBody, &repos); err != nil {
 		log.Fatalf("Error unmarshalling JSON: %v", err)
 	}
 
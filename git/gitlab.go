@@ -1,40 +1,37 @@
 package git
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 )
 
 type Create_Gitlab_Issue struct {
-	Title                                   string   `json:"title"`
-	Created_at                              string   `json:"created_at"`
-	Merge_request_to_resolve_discussions_of int      `json:"merge_request_to_resolve_discussions_of"`
-	Discussion_to_resolve                   string   `json:"discussion_to_resolve"`
-	Iid                                     int      `json:"iid"`
-	Description                             string   `json:"description"`
-	Assignee_ids                            []int    `json:"assignee_ids"`
-	Assignee_id                             int      `json:"assignee_id"`
-	Milestone_id                            int      `json:"milestone_id"`
-	Labels                                  []string `json:"labels"`
-	Add_labels                              []string `json:"add_labels"`
-	Remove_labels                           []string `json:"remove_labels"`
-	Due_date                                string   `json:"due_date"`
-	Confidential                            bool     `json:"confidential"`
-	Discussion_locked                       bool     `json:"discussion_locked"`
-	Issue_type                              string   `json:"issue_type"`
-	Weight                                  int      `json:"weight"`
-	Epic_id                                 int      `json:"epic_id"`
-	Epic_iid                                int      `json:"epic_iid"`
+	Title                                   string   `json:"title,omitempty"`
+	Created_at                              string   `json:"created_at,omitempty"`
+	Merge_request_to_resolve_discussions_of int      `json:"merge_request_to_resolve_discussions_of,omitempty"`
+	Discussion_to_resolve                   string   `json:"discussion_to_resolve,omitempty"`
+	Iid                                     int      `json:"iid,omitempty"`
+	Description                             string   `json:"description,omitempty"`
+	Assignee_ids                            []int    `json:"assignee_ids,omitempty"`
+	Assignee_id                             int      `json:"assignee_id,omitempty"`
+	Milestone_id                            int      `json:"milestone_id,omitempty"`
+	Labels                                  []string `json:"labels,omitempty"`
+	Add_labels                              []string `json:"add_labels,omitempty"`
+	Remove_labels                           []string `json:"remove_labels,omitempty"`
+	Due_date                                string   `json:"due_date,omitempty"`
+	Confidential                            bool     `json:"confidential,omitempty"`
+	Discussion_locked                       bool     `json:"discussion_locked,omitempty"`
+	Issue_type                              string   `json:"issue_type,omitempty"`
+	Weight                                  int      `json:"weight,omitempty"`
+	Epic_id                                 int      `json:"epic_id,omitempty"`
+	Epic_iid                                int      `json:"epic_iid,omitempty"`
 }
 
 type Gitlab_Milestone struct {
-	Id          string `json:"id"`
-	Iid         string `json:"iid"`
-	Project_id  string `json:"project_id"`
-	Group_id    string `json:"group_id"`
+	Id          int    `json:"id"`
+	Iid         int    `json:"iid"`
+	Project_id  int    `json:"project_id"`
+	Group_id    int    `json:"group_id"`
 	Title       string `json:"title"`
 	Description string `json:"description"`
 	State       string `json:"state"`
@@ -42,7 +39,7 @@ type Gitlab_Milestone struct {
 	Updated_at  string `json:"updated_at"`
 	Due_date    string `json:"due_date"`
 	Start_date  string `json:"start_date"`
-	Expired     string `json:"expired"`
+	Expired     bool   `json:"expired"`
 	Web_url     string `json:"web_url"`
 }
 
@@ -91,10 +88,10 @@ type Gitlab_Closed_by struct {
 }
 
 type Gitlab_Iteration struct {
-	Id          string `json:"id"`
-	Iid         string `json:"iid"`
-	Sequence    string `json:"sequence"`
-	Group_id    string `json:"group_id"`
+	Id          int    `json:"id"`
+	Iid         int    `json:"iid"`
+	Sequence    int    `json:"sequence"`
+	Group_id    int    `json:"group_id"`
 	Title       string `json:"title"`
 	Description string `json:"description"`
 	State       string `json:"state"`
@@ -175,67 +172,70 @@ type Create_Gitlab_Issue_Response struct {
 }
 
 type Get_Gitlab_Issue_Response struct {
-	Id                   int              `json:"id"`
-	Iid                  int              `json:"iid"`
-	Project_id           int              `json:"project_id"`
-	Title                string           `json:"title"`
-	Description          string           `json:"description"`
-	State                string           `json:"state"`
-	Created_at           string           `json:"created_at"`
-	Updated_at           string           `json:"updated_at"`
-	Closed_at            string           `json:"closed_at"`
-	Closed_by            Gitlab_Closed_by `json:"closed_by"`
-	Labels               string           `json:"labels"`
-	Milestone            Gitlab_Milestone `json:"milestone"`
-	Assignees            Gitlab_Assignees `json:"assignees"`
-	Author               Gitlab_Author    `json:"author"`
-	Type                 string           `json:"type"`
-	Assignee             Gitlab_Assignees `json:"assignee"`
-	User_notes_count     string           `json:"user_notes_count"`
-	Merge_requests_count string           `json:"merge_requests_count"`
-	Upvotes              string           `json:"upvotes"`
-	Downvotes            string           `json:"downvotes"`
-	Due_date             string           `json:"due_date"`
-	Confidential         bool             `json:"confidential"`
-	Discussion_locked    bool             `json:"discussion_locked"`
-	Issue_type           string           `json:"issue_type"`
-	Web_url              string           `json:"web_url"`
+	Id                   int                `json:"id"`
+	Iid                  int                `json:"iid"`
+	Project_id           int                `json:"project_id"`
+	Title                string             `json:"title"`
+	Description          string             `json:"description"`
+	State                string             `json:"state"`
+	Created_at           string             `json:"created_at"`
+	Updated_at           string             `json:"updated_at"`
+	Closed_at            string             `json:"closed_at"`
+	Closed_by            Gitlab_Closed_by   `json:"closed_by"`
+	Labels               []string           `json:"labels"`
+	Milestone            Gitlab_Milestone   `json:"milestone"`
+	Assignees            []Gitlab_Assignees `json:"assignees"`
+	Author               Gitlab_Author      `json:"author"`
+	Type                 string             `json:"type"`
+	Assignee             Gitlab_Assignees   `json:"assignee"`
+	User_notes_count     int                `json:"user_notes_count"`
+	Merge_requests_count int                `json:"merge_requests_count"`
+	Upvotes              int                `json:"upvotes"`
+	Downvotes            int                `json:"downvotes"`
+	Due_date             string             `json:"due_date"`
+	Confidential         bool               `json:"confidential"`
+	Discussion_locked    bool               `json:"discussion_locked"`
+	Issue_type           string             `json:"issue_type"`
+	Web_url              string             `json:"web_url"`
 	Time_stats           struct {
 		Time_estimate          int    `json:"time_estimate"`
 		Total_time_spent       int    `json:"total_time_spent"`
 		Human_time_estimate    string `json:"human_time_estimate"`
 		Human_total_time_spent string `json:"human_total_time_spent"`
 	} `json:"time_stats"`
-	Task_completion_status string `json:"task_completion_status"`
-	Weight                 string `json:"weight"`
-	Blocking_issues_count  string `json:"blocking_issues_count"`
-	Has_tasks              string `json:"has_tasks"`
-	Task_status            string `json:"task_status"`
-	Links                  struct {
+	Task_completion_status struct {
+		Count           int `json:"count"`
+		Completed_count int `json:"completed_count"`
+	} `json:"task_completion_status"`
+	Weight                int    `json:"weight"`
+	Blocking_issues_count int    `json:"blocking_issues_count"`
+	Has_tasks             bool   `json:"has_tasks"`
+	Task_status           string `json:"task_status"`
+	Links                 struct {
 		Self                   string `json:"self"`
 		Notes                  string `json:"notes"`
 		Award_emoji            string `json:"award_emoji"`
 		Project                string `json:"project"`
 		Closed_as_duplicate_of string `json:"closed_as_duplicate_of"`
-	} `json:"links"`
+	} `json:"_links"`
 	References struct {
 		Short    string `json:"short"`
 		Relative string `json:"relative"`
 		Full     string `json:"full"`
 	} `json:"references"`
 	Severity              string `json:"severity"`
-	Subscribed            string `json:"subscribed"`
-	Moved_to_id           string `json:"moved_to_id"`
-	Imported              string `json:"imported"`
+	Subscribed            bool   `json:"subscribed"`
+	Moved_to_id           int    `json:"moved_to_id"`
+	Imported              bool   `json:"imported"`
 	Imported_from         string `json:"imported_from"`
 	Service_desk_reply_to string `json:"service_desk_reply_to"`
-	Epic_iid              string `json:"epic_iid"`
+	Epic_iid              int    `json:"epic_iid"`
 	Epic                  struct {
-		Id                       string `json:"id"`
-		Iid                      string `json:"iid"`
+		Id                       int    `json:"id"`
+		Iid                      int    `json:"iid"`
 		Title                    string `json:"title"`
 		Url                      string `json:"url"`
-		Group_id                 string `json:"group_id"`
+		Group_id                 int    `json:"group_id"`
 		Human_readable_end_date  string `json:"human_readable_end_date"`
 		Human_readable_timestamp string `json:"human_readable_timestamp"`
 	} `json:"epic"`
@@ -251,59 +251,137 @@ func (c Create_Gitlab_Issue) Create(title, description string) error {
 }
 
 func Make_GitLab_Issue(title, description string) error {
-	var newGitlabRequest Create_Gitlab_Issue
-
-	newGitlabRequest.Title = title
-	newGitlabRequest.Description = description
 
 	GitlabCredentials, err := GenericGitRequest()
 	if err != nil {
 		return err
 	}
 
-	// Convert the struct into JSON using the tags and Marshal
-	jsonData, err := json.Marshal(newGitlabRequest)
+	createdIssue, err := createGitlabIssue(GitlabCredentials, Github_Issue{Title: title, Body: description})
 	if err != nil {
 		return err
 	}
 
-	// Convert the JSON into bytes
-	requestBody := bytes.NewBuffer(jsonData)
+	fmt.Printf(CreatedIssue+"%d %s\n", createdIssue.Number, createdIssue.Html_url)
+
+	return nil
+
+}
+
+func createGitlabIssue(credentials Credentials, issue Github_Issue) (GithubIssueResponse, error) {
+	var newGitlabIssue Create_Gitlab_Issue
 
-	var GitlabID string
+	newGitlabIssue.Title = issue.Title
+	newGitlabIssue.Description = issue.Body
+	newGitlabIssue.Labels = issue.Label
+	newGitlabIssue.Milestone_id = issue.Milestone
+	newGitlabIssue.Due_date = issue.DueDate
+
+	// GitLab assigns by user id rather than username
+	for _, username := range issue.Assignees {
+		id, err := gitlabUserID(credentials, username)
+		if err != nil {
+			return GithubIssueResponse{}, err
+		}
+		newGitlabIssue.Assignee_ids = append(newGitlabIssue.Assignee_ids, id)
+	}
 
 	// Make the request
-	// /api/v4/projects/{id}/issues
-	request, err := http.NewRequest("POST", fmt.Sprintf("https://gitlab"+""+"/api/v4/projects/%s/issues", GitlabID), io.Reader(requestBody))
+	// /api/v4/projects/{id}/issues - the URL encoded path of the project works as the id
+	request, err := newGitlabRequest(credentials, "POST", gitlabProjectPath(credentials)+"/issues", newGitlabIssue)
 	if err != nil {
-		fmt.Printf("Error making the HTTP request %s\n", err)
-		return err
+		return GithubIssueResponse{}, err
 	}
 
-	// Set the required headers
-	// "PRIVATE-TOKEN: <your_access_token>"
-	request.Header.Set("PRIVATE-TOKEN: ", GitlabCredentials.Token)
+	// Complete the request - the shared client handles retries and turns a bad status into an APIError
+	_, responseBody, err := Client.Do(request)
+	if err != nil {
+		return GithubIssueResponse{}, err
+	}
+
+	var createdIssue Get_Gitlab_Issue_Response
+	if err := json.Unmarshal(responseBody, &createdIssue); err != nil {
+		return GithubIssueResponse{}, fmt.Errorf("error unmarshalling response: %w", err)
+	}
+
+	return createdIssue.GithubIssue(), nil
+}
 
-	// Make a new client
-	client := http.Client{}
+// ListGitlabIssues lists the project issues matching the query, converted to the GitHub shape
+func ListGitlabIssues(credentials Credentials, query IssueQuery) ([]GithubIssueResponse, error) {
+	var issues []GithubIssueResponse
 
-	// Complete the request - Client.Do because the http.NewRequest handles the method
-	req, err := client.Do(request)
+	params, err := query.GitlabParams()
 	if err != nil {
-		return err
+		return issues, err
 	}
 
-	if req.StatusCode != http.StatusOK && req.StatusCode != http.StatusCreated {
-		fmt.Println(req.Body)
-		return fmt.Errorf("the response was not positive, %d", req.StatusCode)
+	request, err := newGitlabRequest(credentials, "GET", gitlabProjectPath(credentials)+"/issues?"+params.Encode(), nil)
+	if err != nil {
+		return issues, err
 	}
 
-	fmt.Printf("The response was: %s, %s\n", req.Status, HTTPStatusResponseMeanings[req.Status])
+	for len(issues) < query.Limit {
+		response, responseBody, err := Client.Do(request)
+		if err != nil {
+			return issues, err
+		}
 
-	return nil
+		var page []Get_Gitlab_Issue_Response
+		if err := json.Unmarshal(responseBody, &page); err != nil {
+			return issues, fmt.Errorf("error unmarshalling response: %w", err)
+		}
 
+		for _, gitlabIssue := range page {
+			issues = append(issues, gitlabIssue.GithubIssue())
+		}
+
+		var more bool
+		if request, more = nextPage(request, response); !more {
+			break
+		}
+	}
+
+	if len(issues) == 0 {
+		return issues, ErrNoIssues
+	}
+
+	return issues[:min(len(issues), query.Limit)], nil
 }
 
-func Get_Gitlab_Issues(passedFromCLI bool) {
+// GithubIssue copies the fields Thoth uses into a GithubIssueResponse so GitLab issues can share the same output code
+func (gitlabIssue Get_Gitlab_Issue_Response) GithubIssue() GithubIssueResponse {
+	issue := GithubIssueResponse{
+		Id:         gitlabIssue.Id,
+		Number:     gitlabIssue.Iid,
+		Title:      gitlabIssue.Title,
+		Body:       gitlabIssue.Description,
+		State:      gitlabIssue.State,
+		Comments:   gitlabIssue.User_notes_count,
+		Created_at: gitlabIssue.Created_at,
+		Updated_at: gitlabIssue.Updated_at,
+		Html_url:   gitlabIssue.Web_url,
+		Locked:     gitlabIssue.Discussion_locked,
+	}
+
+	// GitLab calls open issues opened
+	if issue.State == "opened" {
+		issue.State = "open"
+	}
+
+	issue.User.Login = gitlabIssue.Author.Username
+	issue.User.Id = gitlabIssue.Author.Id
+
+	for _, label := range gitlabIssue.Labels {
+		issue.Labels = append(issue.Labels, Github_Label{Name: label})
+	}
+
+	for _, assignee := range gitlabIssue.Assignees {
+		issue.Assignees = append(issue.Assignees, Github_Assignee{Login: assignee.Username})
+	}
+	if len(issue.Assignees) > 0 {
+		issue.Assignee = issue.Assignees[0]
+	}
 
+	return issue
 }
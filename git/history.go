@@ -0,0 +1,138 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Commit is a commit on the current branch and when it was committed
+type Commit struct {
+	Hash string    `json:"commit"`
+	Time time.Time `json:"time"`
+}
+
+// GrepLine is a line git grep found in a commit
+type GrepLine struct {
+	Path string
+	Line int
+	Text string
+}
+
+// Samples are how HistoryCommits can pick commits: every one, or the last one of each day or week
+var Samples = []string{"commit", "day", "week"}
+
+// HistoryCommits lists the commits on the first parent line of HEAD committed after since (the zero time for all of them),
+// keeping the last commit of each day or week unless every is commit. They are returned oldest first
+func HistoryCommits(every string, since time.Time) ([]Commit, error) {
+	arguments := []string{"log", "--first-parent", "--format=%H %ct"}
+	if !since.IsZero() {
+		arguments = append(arguments, fmt.Sprintf("--since=%d", since.Unix()))
+	}
+
+	output, err := runGit(append(arguments, "HEAD")...)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []Commit
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		hash, seconds, found := strings.Cut(line, " ")
+		if !found {
+			continue
+		}
+		unix, err := strconv.ParseInt(seconds, 10, 64)
+		if err != nil {
+			continue
+		}
+		commits = append(commits, Commit{Hash: hash, Time: time.Unix(unix, 0)})
+	}
+
+	return sampleCommits(commits, every)
+}
+
+// The commits come newest first from git log, so the first one seen in each day or week is the last one made in it
+func sampleCommits(newestFirst []Commit, every string) ([]Commit, error) {
+	var period func(time.Time) string
+	switch every {
+	case "commit", "":
+	case "day":
+		period = func(at time.Time) string { return at.Format("2006-01-02") }
+	case "week":
+		period = func(at time.Time) string {
+			year, week := at.ISOWeek()
+			return fmt.Sprintf("%d-%02d", year, week)
+		}
+	default:
+		return nil, fmt.Errorf("can't take a commit every %q, use one of %v", every, Samples)
+	}
+
+	var sampled []Commit
+	seen := map[string]bool{}
+	for _, commit := range newestFirst {
+		if period != nil {
+			key := period(commit.Time)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		sampled = append(sampled, commit)
+	}
+
+	for i, j := 0, len(sampled)-1; i < j; i, j = i+1, j-1 {
+		sampled[i], sampled[j] = sampled[j], sampled[i]
+	}
+	return sampled, nil
+}
+
+// GrepAt finds the lines containing any of the words in the files of the commit, under the current directory, along with
+// the line before each one so a comment above can be read. Binary files are skipped. Paths are relative to the current directory
+func GrepAt(commit string, words []string) ([]GrepLine, error) {
+	arguments := []string{"grep", "-I", "-n", "-z", "-B", "1", "--fixed-strings"}
+	for _, word := range words {
+		arguments = append(arguments, "-e", word)
+	}
+
+	var stderr bytes.Buffer
+	command := exec.Command("git", append(arguments, commit, "--")...)
+	command.Stderr = &stderr
+
+	output, err := command.Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 && stderr.Len() == 0 {
+		// git grep exits with 1 when nothing matched
+		return nil, nil
+	}
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("git grep: %s", message)
+		}
+		return nil, fmt.Errorf("git grep: %w", err)
+	}
+
+	return parseGrep(string(output), commit), nil
+}
+
+// With -z each line is <commit>:<path>\0<line number>\0<text>, and groups of lines are split by --
+func parseGrep(output, commit string) []GrepLine {
+	var lines []GrepLine
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.SplitN(line, "\x00", 3)
+		if len(parts) != 3 {
+			continue
+		}
+
+		number, err := strconv.Atoi(parts[1])
+		if err != nil {
+			continue
+		}
+
+		lines = append(lines, GrepLine{Path: strings.TrimPrefix(parts[0], commit+":"), Line: number, Text: parts[2]})
+	}
+	return lines
+}
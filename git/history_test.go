@@ -0,0 +1,52 @@
+package git
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSampleCommits(t *testing.T) {
+	day := func(d, hour int) time.Time { return time.Date(2026, 3, d, hour, 0, 0, 0, time.UTC) }
+
+	// Newest first, as git log gives them. 2nd to 6th March 2026 is one ISO week, 9th is the next
+	newestFirst := []Commit{
+		{"f", day(9, 10)},
+		{"e", day(6, 18)},
+		{"d", day(6, 9)},
+		{"c", day(3, 12)},
+		{"b", day(2, 15)},
+		{"a", day(2, 8)},
+	}
+
+	tests := map[string]string{"commit": "abcdef", "day": "bcef", "week": "ef"}
+	for every, want := range tests {
+		sampled, err := sampleCommits(newestFirst, every)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got string
+		for _, commit := range sampled {
+			got += commit.Hash
+		}
+		if got != want {
+			t.Errorf("every %s got %s, want %s", every, got, want)
+		}
+	}
+
+	if _, err := sampleCommits(newestFirst, "month"); err == nil {
+		t.Error("an unknown sample should fail")
+	}
+}
+
+func TestParseGrep(t *testing.T) {
+	output := "abc123:cmd/a.go\x0012\x00\t// TODO: one: two\nabc123:b.go\x003\x00x := 1 // FIXME: fix\n"
+
+	lines := parseGrep(output, "abc123")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2: %+v", len(lines), lines)
+	}
+	if lines[0] != (GrepLine{Path: "cmd/a.go", Line: 12, Text: "\t// TODO: one: two"}) || lines[1].Path != "b.go" || lines[1].Line != 3 {
+		t.Errorf("unexpected lines %+v", lines)
+	}
+}
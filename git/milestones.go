@@ -0,0 +1,76 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Github_Milestone is the part of a GitHub milestone used to find one by its due date
+type Github_Milestone struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	State  string `json:"state"`
+	Due_on string `json:"due_on"` // 2026-12-01T08:00:00Z, empty when it has no due date
+}
+
+// Milestones looked up or made this run, so TODOs due on the same day share one even when their issues are made at once
+var (
+	milestoneLock sync.Mutex
+	dueMilestones = map[string]int{}
+)
+
+// DueMilestone returns the number of the open GitHub milestone due on the date (written as 2026-12-01),
+// making one called "Due 2026-12-01" when there isn't one yet
+func DueMilestone(credentials Credentials, date string) (int, error) {
+	milestoneLock.Lock()
+	defer milestoneLock.Unlock()
+
+	key := fmt.Sprintf("%s/%s/%s/%s", credentials.Host, credentials.Owner, credentials.Repo, date)
+	if number, found := dueMilestones[key]; found {
+		return number, nil
+	}
+
+	var milestones []Github_Milestone
+	if err := getAllGithubPages(credentials, fmt.Sprintf("/repos/%s/%s/milestones?state=open&per_page=100", credentials.Owner, credentials.Repo), &milestones); err != nil {
+		return 0, err
+	}
+
+	if number := findDueMilestone(milestones, date); number != 0 {
+		dueMilestones[key] = number
+		return number, nil
+	}
+
+	// GitHub keeps the time too, the morning in UTC is the same date in most time zones
+	request, err := newGithubRequest(credentials, "POST", fmt.Sprintf("/repos/%s/%s/milestones", credentials.Owner, credentials.Repo), map[string]string{
+		"title":  "Due " + date,
+		"due_on": date + "T08:00:00Z",
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	_, responseBody, err := Client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+
+	var created Github_Milestone
+	if err := json.Unmarshal(responseBody, &created); err != nil {
+		return 0, fmt.Errorf("error unmarshalling response: %w", err)
+	}
+
+	dueMilestones[key] = created.Number
+	return created.Number, nil
+}
+
+// The open milestone due on the date, 0 when there isn't one
+func findDueMilestone(milestones []Github_Milestone, date string) int {
+	for _, milestone := range milestones {
+		if milestone.State != "closed" && strings.HasPrefix(milestone.Due_on, date) {
+			return milestone.Number
+		}
+	}
+	return 0
+}
@@ -0,0 +1,18 @@
+package git
+
+import "testing"
+
+func TestFindDueMilestone(t *testing.T) {
+	milestones := []Github_Milestone{
+		{Number: 1, Title: "No date"},
+		{Number: 2, Title: "Old", State: "closed", Due_on: "2026-12-01T08:00:00Z"},
+		{Number: 3, Title: "Due 2026-12-01", State: "open", Due_on: "2026-12-01T08:00:00Z"},
+	}
+
+	if got := findDueMilestone(milestones, "2026-12-01"); got != 3 {
+		t.Errorf("got milestone %d, want the open one, 3", got)
+	}
+	if got := findDueMilestone(milestones, "2027-01-01"); got != 0 {
+		t.Errorf("got milestone %d when none are due then", got)
+	}
+}
@@ -0,0 +1,328 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IssueQuery is a parsed issue search such as: label:bug assignee:me created:>2026-01-01 "parser"
+// It is turned into GitHub search syntax or GitLab query parameters depending on the remote
+type IssueQuery struct {
+	State    string // open, closed or all
+	Labels   []string
+	Assignee string // A username, "me" for the token owner or "none" for unassigned
+	Author   string // A username or "me" for the token owner
+	Created  DateRange
+	Updated  DateRange
+	Text     []string
+	Sort     string // created, updated or comments
+	Order    string // asc or desc
+	Limit    int
+}
+
+// DateRange holds an inclusive From and an exclusive Before, either can be left as the zero time
+type DateRange struct {
+	From   time.Time
+	Before time.Time
+}
+
+const dateLayout = "2006-01-02"
+
+// ParseIssueQuery reads each term of the query, terms without a recognised key: prefix are searched for as text
+func ParseIssueQuery(terms []string) (IssueQuery, error) {
+	query := IssueQuery{
+		State: "open",
+		Sort:  "created",
+		Order: "desc",
+		Limit: 30,
+	}
+
+	for _, term := range terms {
+		key, value, found := strings.Cut(term, ":")
+		if !found || value == "" {
+			query.Text = append(query.Text, term)
+			continue
+		}
+
+		var err error
+		switch strings.ToLower(key) {
+		case "label", "labels":
+			for _, label := range strings.Split(value, ",") {
+				if label = strings.TrimSpace(label); label != "" {
+					query.Labels = append(query.Labels, label)
+				}
+			}
+		case "assignee":
+			query.Assignee = strings.TrimPrefix(value, "@")
+		case "author":
+			query.Author = strings.TrimPrefix(value, "@")
+		case "state", "is":
+			switch value {
+			case "open", "opened":
+				query.State = "open"
+			case "closed", "all":
+				query.State = value
+			default:
+				return query, fmt.Errorf("unknown state %q, use open, closed or all", value)
+			}
+		case "created":
+			query.Created, err = parseDateRange(value)
+		case "updated":
+			query.Updated, err = parseDateRange(value)
+		case "sort":
+			err = query.SetSort(value)
+		default:
+			// Not a key we know so it's probably text with a colon in it
+			query.Text = append(query.Text, term)
+		}
+
+		if err != nil {
+			return query, fmt.Errorf("%s: %w", term, err)
+		}
+	}
+
+	return query, nil
+}
+
+// SetSort checks the sort is one both trackers understand
+func (query *IssueQuery) SetSort(sort string) error {
+	switch sort {
+	case "created", "updated", "comments":
+		query.Sort = sort
+		return nil
+	}
+	return fmt.Errorf("unknown sort %q, use created, updated or comments", sort)
+}
+
+// Accepts >date, >=date, <date, <=date, date..date and a bare date for a single day
+func parseDateRange(value string) (DateRange, error) {
+	var dateRange DateRange
+	var err error
+
+	switch {
+	case strings.Contains(value, ".."):
+		from, before, _ := strings.Cut(value, "..")
+		if from != "*" {
+			if dateRange.From, err = parseDate(from, "the start of the range "); err != nil {
+				return dateRange, err
+			}
+		}
+		if before != "*" {
+			if dateRange.Before, err = parseDate(before, "the end of the range "); err != nil {
+				return dateRange, err
+			}
+			dateRange.Before = dateRange.Before.AddDate(0, 0, 1)
+		}
+	case strings.HasPrefix(value, ">="):
+		dateRange.From, err = parseDate(value[2:], "")
+	case strings.HasPrefix(value, ">"):
+		dateRange.From, err = parseDate(value[1:], "")
+		dateRange.From = dateRange.From.AddDate(0, 0, 1)
+	case strings.HasPrefix(value, "<="):
+		dateRange.Before, err = parseDate(value[2:], "")
+		dateRange.Before = dateRange.Before.AddDate(0, 0, 1)
+	case strings.HasPrefix(value, "<"):
+		dateRange.Before, err = parseDate(value[1:], "")
+	default:
+		dateRange.From, err = parseDate(value, "")
+		dateRange.Before = dateRange.From.AddDate(0, 0, 1)
+	}
+
+	if err != nil {
+		return DateRange{}, err
+	}
+
+	return dateRange, nil
+}
+
+// The error says which value was wrong, side is "the start of the range " or "the end of the range " for date..date
+func parseDate(value, side string) (time.Time, error) {
+	date, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return date, fmt.Errorf("%s%q isn't a date, use YYYY-MM-DD", side, value)
+	}
+	return date, nil
+}
+
+// GitHub's range syntax is inclusive at both ends so the exclusive Before becomes the day before
+func (dateRange DateRange) githubQualifier(key string) string {
+	switch {
+	case dateRange.From.IsZero() && dateRange.Before.IsZero():
+		return ""
+	case dateRange.Before.IsZero():
+		return fmt.Sprintf("%s:>=%s", key, dateRange.From.Format(dateLayout))
+	case dateRange.From.IsZero():
+		return fmt.Sprintf("%s:<%s", key, dateRange.Before.Format(dateLayout))
+	}
+	return fmt.Sprintf("%s:%s..%s", key, dateRange.From.Format(dateLayout), dateRange.Before.AddDate(0, 0, -1).Format(dateLayout))
+}
+
+// GithubSearch returns the q parameter for the search API, limited to issues in the repository
+func (query IssueQuery) GithubSearch(owner, repo string) string {
+	terms := []string{fmt.Sprintf("repo:%s/%s", owner, repo), "is:issue"}
+
+	if query.State != "all" {
+		terms = append(terms, "state:"+query.State)
+	}
+
+	for _, label := range query.Labels {
+		terms = append(terms, "label:"+quoteSearchTerm(label))
+	}
+
+	switch query.Assignee {
+	case "":
+	case "me":
+		terms = append(terms, "assignee:@me")
+	case "none":
+		terms = append(terms, "no:assignee")
+	default:
+		terms = append(terms, "assignee:"+query.Assignee)
+	}
+
+	switch query.Author {
+	case "":
+	case "me":
+		terms = append(terms, "author:@me")
+	default:
+		terms = append(terms, "author:"+query.Author)
+	}
+
+	for _, qualifier := range []string{query.Created.githubQualifier("created"), query.Updated.githubQualifier("updated")} {
+		if qualifier != "" {
+			terms = append(terms, qualifier)
+		}
+	}
+
+	for _, text := range query.Text {
+		terms = append(terms, quoteSearchTerm(text))
+	}
+
+	return strings.Join(terms, " ")
+}
+
+func quoteSearchTerm(term string) string {
+	if strings.ContainsAny(term, " \t:") {
+		return `"` + strings.ReplaceAll(term, `"`, "") + `"`
+	}
+	return term
+}
+
+// GitlabParams returns the query parameters for the project issues endpoint
+func (query IssueQuery) GitlabParams() (url.Values, error) {
+	params := url.Values{}
+
+	switch query.State {
+	case "open":
+		params.Set("state", "opened")
+	case "closed":
+		params.Set("state", "closed")
+	}
+
+	if len(query.Labels) > 0 {
+		params.Set("labels", strings.Join(query.Labels, ","))
+	}
+
+	switch query.Assignee {
+	case "":
+	case "me":
+		params.Set("scope", "assigned_to_me")
+	case "none":
+		params.Set("assignee_id", "None")
+	default:
+		params.Set("assignee_username", query.Assignee)
+	}
+
+	switch query.Author {
+	case "":
+	case "me":
+		if params.Get("scope") != "" {
+			return params, fmt.Errorf("GitLab can't filter on assignee:me and author:me at the same time")
+		}
+		params.Set("scope", "created_by_me")
+	default:
+		params.Set("author_username", query.Author)
+	}
+
+	// GitLab's after / before are both inclusive
+	setGitlabDates := func(dateRange DateRange, afterKey, beforeKey string) {
+		if !dateRange.From.IsZero() {
+			params.Set(afterKey, dateRange.From.Format(time.RFC3339))
+		}
+		if !dateRange.Before.IsZero() {
+			params.Set(beforeKey, dateRange.Before.Add(-time.Second).Format(time.RFC3339))
+		}
+	}
+	setGitlabDates(query.Created, "created_after", "created_before")
+	setGitlabDates(query.Updated, "updated_after", "updated_before")
+
+	if len(query.Text) > 0 {
+		params.Set("search", strings.Join(query.Text, " "))
+	}
+
+	switch query.Sort {
+	case "created":
+		params.Set("order_by", "created_at")
+	case "updated":
+		params.Set("order_by", "updated_at")
+	case "comments":
+		return params, fmt.Errorf("GitLab can't sort issues by comments, use created or updated")
+	}
+	params.Set("sort", query.Order)
+	params.Set("per_page", strconv.Itoa(min(max(query.Limit, 1), 100)))
+
+	return params, nil
+}
+
+// SearchIssues runs the query against the tracker for the remote, GitLab issues are converted to the GitHub shape so they print the same
+func SearchIssues(credentials Credentials, query IssueQuery) ([]GithubIssueResponse, error) {
+	if isGitlabHost(credentials.Host) {
+		return ListGitlabIssues(credentials, query)
+	}
+	return searchGithubIssues(credentials, query)
+}
+
+func searchGithubIssues(credentials Credentials, query IssueQuery) ([]GithubIssueResponse, error) {
+	var issues []GithubIssueResponse
+
+	params := url.Values{}
+	params.Set("q", query.GithubSearch(credentials.Owner, credentials.Repo))
+	params.Set("sort", query.Sort)
+	params.Set("order", query.Order)
+	params.Set("per_page", strconv.Itoa(min(max(query.Limit, 1), 100)))
+
+	request, err := newGithubRequest(credentials, "GET", "/search/issues?"+params.Encode(), nil)
+	if err != nil {
+		return issues, err
+	}
+
+	for len(issues) < query.Limit {
+		response, responseBody, err := Client.Do(request)
+		if err != nil {
+			return issues, err
+		}
+
+		var page struct {
+			Total_count int                   `json:"total_count"`
+			Items       []GithubIssueResponse `json:"items"`
+		}
+		if err := json.Unmarshal(responseBody, &page); err != nil {
+			return issues, fmt.Errorf("error unmarshalling response: %w", err)
+		}
+		issues = append(issues, page.Items...)
+
+		var more bool
+		if request, more = nextPage(request, response); !more {
+			break
+		}
+	}
+
+	if len(issues) == 0 {
+		return issues, ErrNoIssues
+	}
+
+	return issues[:min(len(issues), query.Limit)], nil
+}
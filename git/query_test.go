@@ -0,0 +1,49 @@
+package git
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseIssueQuery(t *testing.T) {
+	t.Log("Testing ParseIssueQuery and the GitHub / GitLab translations")
+
+	query, err := ParseIssueQuery([]string{"label:bug", "assignee:me", "created:>2026-01-01", "parser", "state:all"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	search := query.GithubSearch("jonathon-chew", "Thoth")
+	expected := "repo:jonathon-chew/Thoth is:issue label:bug assignee:@me created:>=2026-01-02 parser"
+	if search != expected {
+		t.Errorf("GitHub search was %q, expected %q", search, expected)
+	}
+
+	params, err := query.GitlabParams()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if params.Get("labels") != "bug" || params.Get("scope") != "assigned_to_me" || params.Get("created_after") != "2026-01-02T00:00:00Z" || params.Get("search") != "parser" || params.Has("state") {
+		t.Errorf("Unexpected GitLab params %v", params)
+	}
+
+	ranged, err := ParseIssueQuery([]string{"updated:2026-01-01..2026-01-31"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if qualifier := ranged.Updated.githubQualifier("updated"); qualifier != "updated:2026-01-01..2026-01-31" {
+		t.Errorf("Expected the range to round trip, got %q", qualifier)
+	}
+
+	if _, err := ParseIssueQuery([]string{"created:yesterday"}); err == nil || !strings.Contains(err.Error(), `"yesterday"`) || !strings.Contains(err.Error(), "YYYY-MM-DD") {
+		t.Errorf("Expected an error naming the date that can't be parsed, got %v", err)
+	}
+
+	for term, side := range map[string]string{"updated:2026-13-01..2026-01-31": "start", "updated:2026-01-01..soon": "end"} {
+		if _, err := ParseIssueQuery([]string{term}); err == nil || !strings.Contains(err.Error(), "the "+side+" of the range") || !strings.Contains(err.Error(), "YYYY-MM-DD") {
+			t.Errorf("Expected the error for %s to name the %s of the range, got %v", term, side, err)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var todoReference = regexp.MustCompile(`This is from file (\S+) on line (\d+)`)
+
+// TODOReference is the line in an issue body saying where its TODO is
+func TODOReference(path string, line int) string {
+	return fmt.Sprintf("This is from file %s on line %d", path, line)
+}
+
+// ParseTODOReference finds the file and line written by TODOReference
+func ParseTODOReference(body string) (string, int, bool) {
+	match := todoReference.FindStringSubmatch(body)
+	if match == nil {
+		return "", 0, false
+	}
+
+	line, _ := strconv.Atoi(match[2])
+	return match[1], line, true
+}
+
+// NormalizeTitle is the title or TODO without the number, keyword, comment markers, case and punctuation, the form used to compare them
+func NormalizeTitle(title string) string {
+	return strings.Join(titleWords(title), " ")
+}
+
+// UpdateTODOBody points the reference in the body at the path and line, and replaces the fingerprint comment
+// Either is added to the end when the body doesn't have one yet
+func UpdateTODOBody(body, path string, line int, fingerprint Fingerprint) string {
+	reference := TODOReference(path, line)
+	if todoReference.MatchString(body) {
+		body = todoReference.ReplaceAllLiteralString(body, reference)
+	} else {
+		body = strings.TrimRight(body, "\n") + "\n\n" + reference + "\n"
+	}
+
+	if fingerprintComment.MatchString(body) {
+		return fingerprintComment.ReplaceAllLiteralString(body, fingerprint.Comment())
+	}
+
+	return strings.TrimRight(body, "\n") + "\n\n" + fingerprint.Comment() + "\n"
+}
@@ -0,0 +1,31 @@
+package git
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUpdateTODOBody(t *testing.T) {
+	old := NewFingerprint("// TODO: cache the response", []string{"client.go"})
+	moved := NewFingerprint("// TODO: cache the whole response", []string{"http/client.go", "client.go"})
+
+	body := "Some notes someone added\n\n" + TODOReference("client.go", 12) + "\n\n" + old.Comment() + "\n"
+	updated := UpdateTODOBody(body, "http/client.go", 40, moved)
+
+	if path, line, found := ParseTODOReference(updated); !found || path != "http/client.go" || line != 40 {
+		t.Errorf("reference is %s:%d (found %v) in %q", path, line, found, updated)
+	}
+
+	if fingerprint, _ := ParseFingerprint(updated); fingerprint.ID != moved.ID || strings.Count(updated, "thoth:fingerprint") != 1 {
+		t.Errorf("fingerprint wasn't replaced in %q", updated)
+	}
+
+	if !strings.HasPrefix(updated, "Some notes someone added") {
+		t.Errorf("the rest of the body was changed: %q", updated)
+	}
+
+	added := UpdateTODOBody("Written by hand", "main.go", 3, moved)
+	if _, _, found := ParseTODOReference(added); !found || !strings.Contains(added, moved.Comment()) {
+		t.Errorf("reference and fingerprint weren't added to %q", added)
+	}
+}
@@ -0,0 +1,143 @@
+package git
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// IssueTemplate is a markdown issue template from .github/ISSUE_TEMPLATE or .gitlab/issue_templates
+// GitHub templates carry their name, title, labels and assignees in front matter, GitLab ones are just the body
+type IssueTemplate struct {
+	Path      string
+	Name      string
+	About     string
+	Title     string
+	Labels    []string
+	Assignees []string
+	Body      string
+}
+
+var issueTemplateDirectories = []string{
+	filepath.Join(".github", "ISSUE_TEMPLATE"),
+	filepath.Join(".gitlab", "issue_templates"),
+}
+
+// FindIssueTemplates reads every markdown template under root, sorted by name
+func FindIssueTemplates(root string) ([]IssueTemplate, error) {
+	var templates []IssueTemplate
+
+	for _, directory := range issueTemplateDirectories {
+		paths, err := filepath.Glob(filepath.Join(root, directory, "*.md"))
+		if err != nil {
+			return templates, err
+		}
+
+		for _, path := range paths {
+			contents, err := os.ReadFile(path)
+			if err != nil {
+				return templates, err
+			}
+			templates = append(templates, parseIssueTemplate(path, string(contents)))
+		}
+	}
+
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+
+	return templates, nil
+}
+
+// Matches is true when the choice is the template's name or its file name, ignoring case
+func (template IssueTemplate) Matches(choice string) bool {
+	fileName := strings.TrimSuffix(filepath.Base(template.Path), filepath.Ext(template.Path))
+	return strings.EqualFold(choice, template.Name) || strings.EqualFold(choice, fileName)
+}
+
+func parseIssueTemplate(path, contents string) IssueTemplate {
+	template := IssueTemplate{
+		Path: path,
+		Name: strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+		Body: strings.TrimSpace(contents),
+	}
+
+	contents = strings.ReplaceAll(contents, "\r\n", "\n")
+	if !strings.HasPrefix(contents, "---\n") {
+		return template
+	}
+
+	frontMatter, body, found := strings.Cut(contents[4:], "\n---")
+	if !found {
+		return template
+	}
+	template.Body = strings.TrimSpace(strings.TrimPrefix(body, "\n"))
+
+	var listKey string
+	scanner := bufio.NewScanner(strings.NewReader(frontMatter))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		// The block form of a list:
+		// labels:
+		//   - bug
+		if item, isItem := strings.CutPrefix(trimmed, "- "); isItem && listKey != "" {
+			template.addFrontMatterList(listKey, []string{unquote(item)})
+			continue
+		}
+
+		key, value, found := strings.Cut(trimmed, ":")
+		if !found {
+			continue
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		listKey = ""
+
+		switch key {
+		case "name":
+			template.Name = unquote(value)
+		case "about":
+			template.About = unquote(value)
+		case "title":
+			template.Title = unquote(value)
+		case "labels", "assignees":
+			if value == "" {
+				listKey = key
+				continue
+			}
+			template.addFrontMatterList(key, splitFrontMatterList(value))
+		}
+	}
+
+	return template
+}
+
+func (template *IssueTemplate) addFrontMatterList(key string, values []string) {
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		if key == "labels" {
+			template.Labels = append(template.Labels, value)
+		} else {
+			template.Assignees = append(template.Assignees, strings.TrimPrefix(value, "@"))
+		}
+	}
+}
+
+// Handles both labels: bug, triage and labels: ["bug", "triage"]
+func splitFrontMatterList(value string) []string {
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+
+	var values []string
+	for _, item := range strings.Split(value, ",") {
+		values = append(values, unquote(item))
+	}
+
+	return values
+}
+
+func unquote(value string) string {
+	return strings.Trim(strings.TrimSpace(value), `"'`)
+}
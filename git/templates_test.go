@@ -0,0 +1,80 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseIssueTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		want     IssueTemplate
+	}{
+		{
+			name:     "inline lists",
+			contents: "---\nname: Bug report\nabout: 'Something is broken'\ntitle: \"[BUG] \"\nlabels: [bug, \"needs triage\"]\nassignees: '@sam, alex'\n---\n\n## Steps\n",
+			want:     IssueTemplate{Name: "Bug report", About: "Something is broken", Title: "[BUG] ", Labels: []string{"bug", "needs triage"}, Assignees: []string{"sam", "alex"}, Body: "## Steps"},
+		},
+		{
+			name:     "block lists",
+			contents: "---\nname: Feature\nlabels:\n  - enhancement\n  - \"p2\"\nassignees:\n  - '@sam'\ntitle: Feature\n---\nWhat should it do?\n",
+			want:     IssueTemplate{Name: "Feature", Title: "Feature", Labels: []string{"enhancement", "p2"}, Assignees: []string{"sam"}, Body: "What should it do?"},
+		},
+		{
+			name:     "crlf",
+			contents: "---\r\nname: Windows\r\nlabels: [a, \"b\"]\r\n---\r\nBody line\r\n",
+			want:     IssueTemplate{Name: "Windows", Labels: []string{"a", "b"}, Body: "Body line"},
+		},
+		{
+			name:     "gitlab without front matter",
+			contents: "## Summary\n\n- not a label\n",
+			want:     IssueTemplate{Name: "gitlab without front matter", Body: "## Summary\n\n- not a label"},
+		},
+		{
+			name:     "unclosed front matter",
+			contents: "---\nname: Broken\n",
+			want:     IssueTemplate{Name: "unclosed front matter", Body: "---\nname: Broken"},
+		},
+	}
+
+	for _, test := range tests {
+		got := parseIssueTemplate(test.name+".md", test.contents)
+		if got.Name != test.want.Name || got.About != test.want.About || got.Title != test.want.Title || got.Body != test.want.Body ||
+			strings.Join(got.Labels, "|") != strings.Join(test.want.Labels, "|") || strings.Join(got.Assignees, "|") != strings.Join(test.want.Assignees, "|") {
+			t.Errorf("%s: got %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestFindIssueTemplates(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		".github/ISSUE_TEMPLATE/bug.md":      "---\nname: Bug report\nlabels: bug\n---\nSteps\n",
+		".github/ISSUE_TEMPLATE/config.yml":  "blank_issues_enabled: false\n",
+		".gitlab/issue_templates/Feature.md": "What should it do?\n",
+	}
+	for path, contents := range files {
+		full := filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	templates, err := FindIssueTemplates(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 2 || templates[0].Name != "Bug report" || templates[1].Name != "Feature" {
+		t.Fatalf("unexpected templates %+v", templates)
+	}
+
+	if !templates[0].Matches("bug") || !templates[0].Matches("BUG REPORT") || templates[0].Matches("feature") {
+		t.Error("a template should match its name or file name, ignoring case")
+	}
+}
@@ -0,0 +1,212 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type Github_Comment struct {
+	Id         int    `json:"id"`
+	Body       string `json:"body"`
+	Created_at string `json:"created_at"`
+	Updated_at string `json:"updated_at"`
+	Html_url   string `json:"html_url"`
+	User       struct {
+		Login string `json:"login"`
+	} `json:"user"`
+}
+
+type Github_Event struct {
+	Id         int    `json:"id"`
+	Event      string `json:"event"`
+	Created_at string `json:"created_at"`
+	Commit_id  string `json:"commit_id"`
+	Actor      struct {
+		Login string `json:"login"`
+	} `json:"actor"`
+	Label    Github_Label    `json:"label"`
+	Assignee Github_Assignee `json:"assignee"`
+	Rename   struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	} `json:"rename"`
+	State_reason string `json:"state_reason"`
+}
+
+// GitLab keeps comments and system events (label changes, closing etc) together as notes
+type Gitlab_Note struct {
+	Id         int           `json:"id"`
+	Body       string        `json:"body"`
+	Author     Gitlab_Author `json:"author"`
+	Created_at string        `json:"created_at"`
+	Updated_at string        `json:"updated_at"`
+	System     bool          `json:"system"`
+}
+
+// Describe reads the event as a short sentence for the timeline
+func (event Github_Event) Describe() string {
+	switch event.Event {
+	case "labeled":
+		return fmt.Sprintf("added the %s label", event.Label.Name)
+	case "unlabeled":
+		return fmt.Sprintf("removed the %s label", event.Label.Name)
+	case "assigned":
+		return fmt.Sprintf("assigned %s", event.Assignee.Login)
+	case "unassigned":
+		return fmt.Sprintf("unassigned %s", event.Assignee.Login)
+	case "renamed":
+		return fmt.Sprintf("changed the title from %q to %q", event.Rename.From, event.Rename.To)
+	case "closed":
+		if event.State_reason != "" {
+			return fmt.Sprintf("closed this as %s", strings.ReplaceAll(event.State_reason, "_", " "))
+		}
+		return "closed this"
+	case "reopened":
+		return "reopened this"
+	case "referenced":
+		return fmt.Sprintf("referenced this in commit %.7s", event.Commit_id)
+	}
+
+	// GitLab system notes are already a sentence so only GitHub's event names need tidying up
+	if strings.Contains(event.Event, " ") {
+		return event.Event
+	}
+	return strings.ReplaceAll(event.Event, "_", " ")
+}
+
+// GetIssue fetches a single issue by its number (the iid on GitLab)
+func GetIssue(credentials Credentials, number int) (GithubIssueResponse, error) {
+	var issue GithubIssueResponse
+
+	if isGitlabHost(credentials.Host) {
+		gitlabIssue, err := getGitlabIssue(credentials, number)
+		if err != nil {
+			return issue, err
+		}
+		return gitlabIssue.GithubIssue(), nil
+	}
+
+	request, err := newGithubRequest(credentials, "GET", fmt.Sprintf("/repos/%s/%s/issues/%d", credentials.Owner, credentials.Repo, number), nil)
+	if err != nil {
+		return issue, err
+	}
+
+	_, responseBody, err := Client.Do(request)
+	if err != nil {
+		return issue, err
+	}
+
+	if err := json.Unmarshal(responseBody, &issue); err != nil {
+		return issue, fmt.Errorf("error unmarshalling response: %w", err)
+	}
+
+	return issue, nil
+}
+
+func getGitlabIssue(credentials Credentials, number int) (Get_Gitlab_Issue_Response, error) {
+	var gitlabIssue Get_Gitlab_Issue_Response
+
+	request, err := newGitlabRequest(credentials, "GET", fmt.Sprintf("%s/issues/%d", gitlabProjectPath(credentials), number), nil)
+	if err != nil {
+		return gitlabIssue, err
+	}
+
+	_, responseBody, err := Client.Do(request)
+	if err != nil {
+		return gitlabIssue, err
+	}
+
+	if err := json.Unmarshal(responseBody, &gitlabIssue); err != nil {
+		return gitlabIssue, fmt.Errorf("error unmarshalling response: %w", err)
+	}
+
+	return gitlabIssue, nil
+}
+
+// GetIssueTimeline fetches the comments and events on an issue, each in the order they happened
+func GetIssueTimeline(credentials Credentials, number int) ([]Github_Comment, []Github_Event, error) {
+	if isGitlabHost(credentials.Host) {
+		return getGitlabTimeline(credentials, number)
+	}
+
+	var comments []Github_Comment
+	var events []Github_Event
+
+	if err := getAllGithubPages(credentials, fmt.Sprintf("/repos/%s/%s/issues/%d/comments?per_page=100", credentials.Owner, credentials.Repo, number), &comments); err != nil {
+		return comments, events, err
+	}
+
+	if err := getAllGithubPages(credentials, fmt.Sprintf("/repos/%s/%s/issues/%d/events?per_page=100", credentials.Owner, credentials.Repo, number), &events); err != nil {
+		return comments, events, err
+	}
+
+	return comments, events, nil
+}
+
+// Follow the Link header adding each page of results onto the slice that into points to
+func getAllGithubPages[T any](credentials Credentials, path string, into *[]T) error {
+	request, err := newGithubRequest(credentials, "GET", path, nil)
+	if err != nil {
+		return err
+	}
+
+	for {
+		response, responseBody, err := Client.Do(request)
+		if err != nil {
+			return err
+		}
+
+		var page []T
+		if err := json.Unmarshal(responseBody, &page); err != nil {
+			return fmt.Errorf("error unmarshalling response: %w", err)
+		}
+		*into = append(*into, page...)
+
+		var more bool
+		if request, more = nextPage(request, response); !more {
+			return nil
+		}
+	}
+}
+
+func getGitlabTimeline(credentials Credentials, number int) ([]Github_Comment, []Github_Event, error) {
+	var comments []Github_Comment
+	var events []Github_Event
+
+	request, err := newGitlabRequest(credentials, "GET", fmt.Sprintf("%s/issues/%d/notes?sort=asc&per_page=100", gitlabProjectPath(credentials), number), nil)
+	if err != nil {
+		return comments, events, err
+	}
+
+	for {
+		response, responseBody, err := Client.Do(request)
+		if err != nil {
+			return comments, events, err
+		}
+
+		var notes []Gitlab_Note
+		if err := json.Unmarshal(responseBody, &notes); err != nil {
+			return comments, events, fmt.Errorf("error unmarshalling response: %w", err)
+		}
+
+		for _, note := range notes {
+			if note.System {
+				// System notes are already a sentence e.g. "added ~bug label"
+				event := Github_Event{Id: note.Id, Event: note.Body, Created_at: note.Created_at}
+				event.Actor.Login = note.Author.Username
+				events = append(events, event)
+				continue
+			}
+
+			comment := Github_Comment{Id: note.Id, Body: note.Body, Created_at: note.Created_at, Updated_at: note.Updated_at}
+			comment.User.Login = note.Author.Username
+			comments = append(comments, comment)
+		}
+
+		var more bool
+		if request, more = nextPage(request, response); !more {
+			return comments, events, nil
+		}
+	}
+}
@@ -1,41 +1,57 @@
 package main
 
 import (
-	"bufio"
-	"errors"
 	"fmt"
 	"os"
 	"slices"
-	"strings"
 
-	utils "github.com/jonathon-chew/Thoth/Utils"
 	"github.com/jonathon-chew/Thoth/cmd"
 	"github.com/jonathon-chew/Thoth/git"
+	"github.com/jonathon-chew/Thoth/scanner"
 )
 
 func main() {
 
 	// Check if there are arguments have been input - if so run through the cmd module
-	if len(os.Args[1:]) >= 1 {
+	if len(os.Args[1:]) >= 1 && os.Args[1] != "scan" {
 		ErrProcessingCmd := cmd.CLI(os.Args[1:])
 		if ErrProcessingCmd != nil {
 
 			// Print that there was an issue and the command passed in
 			fmt.Printf("Error parsing the command line argument, %v\n", ErrProcessingCmd)
 
-			// Return with a bad status code to allow this to be checked in other programmes whether it was succesfully even understood!
-			os.Exit(1)
+			// Return with a status code to allow this to be checked in other programmes whether it was succesfully even understood!
+			os.Exit(cmd.ExitCode(ErrProcessingCmd))
 		} else {
 			// If there is no error exit the main function - this stops the deafult behaviour from writing to the file
 			return
 		}
 	}
-	// CHECK to see if their is a git folder
-	// Initilaise the known files to ignore!
-	unwantedFiles := []string{".localized", ".DS_Store", ".gitignore"}
-	unwantedExtentions := []string{".app", ".exe", ".elf", ".md"}
-	fileList := utils.FindFilesInCurrentDirectory()
 
+	// thoth scan --workspace <folder> scans every repository in the folder, each one in its own thoth scan
+	if len(os.Args[1:]) >= 1 {
+		ranWorkspace, workspaceErr := cmd.ScanWorkspace(os.Args[2:])
+		if workspaceErr != nil {
+			fmt.Printf("[ERROR]: %v\n", workspaceErr)
+			os.Exit(cmd.ExitCode(workspaceErr))
+		}
+		if ranWorkspace {
+			return
+		}
+	}
+
+	// thoth scan is the default TODO scan, optionally limited to the lines changed since a ref or the staged lines
+	var changedLines git.ChangedLines
+	if len(os.Args[1:]) >= 1 {
+		var scanFilterErr error
+		changedLines, scanFilterErr = cmd.ScanFilter(os.Args[2:])
+		if scanFilterErr != nil {
+			fmt.Printf("Error parsing the command line argument, %v\n", scanFilterErr)
+			os.Exit(cmd.ExitCode(scanFilterErr))
+		}
+	}
+
+	// CHECK to see if their is a git folder
 	if !git.FindGitFolder() {
 		os.Exit(1)
 	}
@@ -44,124 +60,94 @@ func main() {
 	_, remoteOriginErr := git.GetRemoteOrigin()
 	if remoteOriginErr != nil {
 		fmt.Printf("[ERROR]: %s\n", remoteOriginErr)
-		os.Exit(1)
+		os.Exit(cmd.ExitCode(remoteOriginErr))
 	}
 
-	// Get a list of all current issues
-	listOfGithubIssues, githubErr := git.ListGithubIssues(false)
-	if githubErr != nil {
-		if errors.Is(githubErr, fmt.Errorf("there were no github issues")) {
-			fmt.Printf("[ERROR]: There was an error getting issues: %v\n", githubErr)
-			return
-		}
+	credentials, credentialsErr := git.GenericGitRequest()
+	if credentialsErr != nil {
+		fmt.Printf("[ERROR]: %s\n", credentialsErr)
+		os.Exit(cmd.ExitCode(credentialsErr))
 	}
 
-	// Get the number of existing issues
-	CurrentNumberOfIssues := len(listOfGithubIssues)
-
-	var foundNewTODO bool = false
-	for _, fileName := range fileList {
-
-		// Keep going straight away if it's a directory
-		if fileName.IsDir() || !strings.Contains(fileName.Name(), ".") {
-			continue
-		}
-
-		// Get the lines of the file
-		var fileLine []string
-
-		// Set the file name
-		var filePath = fileName.Name()
-
-		// Make sure it's not one of the known unwanted files to edit
-		if slices.Contains(unwantedFiles, filePath) {
-			continue
-		}
-
-		// Set up variables to be used to check through eveyting that's already in place
-		var unwantedExtention bool = false
-		var updatedFile bool = false
+	// Fetch the current issues once so every new TODO can be checked against them before an issue is made
+	filer, filerErr := cmd.NewIssueFiler(credentials, ".")
+	if filerErr != nil {
+		fmt.Printf("[ERROR]: There was an error getting issues: %v\n", filerErr)
+		os.Exit(cmd.ExitCode(filerErr))
+	}
 
-		// ignore binary files!
-		for _, extension := range unwantedExtentions {
-			if strings.Contains(filePath, extension) {
-				unwantedExtention = true
-				break
-			}
-		}
+	// Read every file first, then talk to the API, then edit the files
+	options := scanner.Options{}
+	if changedLines != nil {
+		options.Lines = changedLines
+	}
 
-		if unwantedExtention {
-			continue
-		}
+	// --staged reads the staged version of each file, the working tree can have moved on since
+	var findings, stagedFindings []scanner.Finding
+	var scanErr error
+	if len(os.Args[1:]) >= 1 && cmd.StagedRequested(os.Args[2:]) {
+		findings, stagedFindings, scanErr = cmd.ScanStaged(changedLines)
+	} else {
+		findings, scanErr = scanner.New(options).ScanTree(".")
+	}
+	if scanErr != nil {
+		fmt.Printf("[ERROR]: %v\n", scanErr)
+	}
 
-		// Look for to dos in the file
-		file, err := os.Open(filePath)
-		if err != nil {
-			return
+	// --review asks about each new TODO first, only what was decided there is done
+	var reviewed map[int]cmd.Review
+	var ignored []scanner.Finding
+	if len(os.Args[1:]) >= 1 && cmd.ReviewRequested(os.Args[2:]) {
+		var reviewErr error
+		reviewed, ignored, reviewErr = cmd.ReviewFindings(filer, findings)
+		if reviewErr != nil {
+			fmt.Printf("[ERROR]: %v\n", reviewErr)
+			os.Exit(cmd.ExitCode(reviewErr))
 		}
+	}
 
-		var lineNumber int
-		scanner := bufio.NewScanner(file)
-		for scanner.Scan() {
-			lineNumber++
-			line := scanner.Text()
-
-			// This is adding a number to the start of the todo as a way to keep track and act as a guard against duplicating issues!
-			if strings.Contains(line, "TODO: ") && !strings.Contains(line, ") TODO") {
-
-				// Find the with the TODO in it
-				var replaceString string = fmt.Sprintf("(#%d) TODO", CurrentNumberOfIssues+1)
-
-				// Replace the issue with the replace string which now has a number in it
-				line = strings.Replace(line, "TODO", replaceString, 1)
-
-				// Print this to the screen
-				fmt.Printf("I would like to make a github issue for: %s\nThe title is %s\nThe body is: %s on line %d\n", strings.TrimSpace(line), strings.TrimSpace(line), fileName.Name(), lineNumber)
-
-				// Incriment the number of current issues - for the next time this needs to be used
-				CurrentNumberOfIssues += 1
-
-				// Check whether the issue already exists...
-				git.MakeGithubIssue(line, fmt.Sprintf("This is from file %s on line %d\n", fileName.Name(), lineNumber))
-
-				// Conditional if something has been updated, some actions needs to happen outside of the loop
-				updatedFile, foundNewTODO = true, true
-
-			} else if strings.Contains(line, "TODO: ") && strings.Contains(line, ") TODO") {
-				// This finds OLD TODOs
+	numbers, fileErr := cmd.FileFindings(filer, findings, reviewed)
 
-				// (#22) TODO: If github issue not in the list of old todos close issue
-				/* _, removeError := RemoveLineDueToGithubIssue(line, listOfGithubIssues)
-				if removeError == nil {
-					// (#21) TODO: If todo in the list of old todos and no longer open on github, remove line
-					line = ""
-				} */
+	rewriter := scanner.NewRewriter()
+	for index, finding := range findings {
+		rewriter.Number(finding, numbers[index])
+	}
+	for _, finding := range ignored {
+		rewriter.Ignore(finding)
+	}
 
-				// issue here being TOO powerful, when run on itself it deletes the if statements! Check for number?
+	// With --staged the same lines are numbered in the staged files, so the commit has the numbers without staging anything else
+	if stagedFindings != nil {
+		stagedRewriter := scanner.NewRewriter()
+		for index, finding := range stagedFindings {
+			stagedRewriter.Number(finding, numbers[index])
+			if slices.Contains(ignored, findings[index]) {
+				stagedRewriter.Ignore(finding)
 			}
-
-			// Regardless of whether a line has changed or not, add it into the list of lines to write back in
-			fileLine = append(fileLine, line)
 		}
 
-		if err := scanner.Err(); err != nil {
-			fmt.Println("Error reading file: ", err)
-			return
+		readStaged := func(path string) (string, error) { return git.FileAt("", path) }
+		if _, stageErr := stagedRewriter.ApplyTo(readStaged, git.WriteStaged); stageErr != nil {
+			fmt.Println("Error staging the numbered lines:", stageErr)
+			os.Exit(1)
 		}
+	}
 
-		// Write modified content back to the file
-		if updatedFile {
-
-			// Write the result of the parsing of the file to the file again!
-			err = os.WriteFile(filePath, []byte(strings.Join(fileLine, "\n")), 0644)
-			if err != nil {
-				fmt.Println("Error writing file:", err)
-				return
-			}
-		}
+	numbered, numberErr := rewriter.Apply()
+	if numberErr != nil {
+		fmt.Println("Error writing file:", numberErr)
+		os.Exit(1)
 	}
 
-	if !foundNewTODO {
+	if numbered == 0 && changedLines != nil {
+		fmt.Println("No new todo found in the changed lines")
+	} else if numbered == 0 {
 		fmt.Println("No new todo found in any file in this directory")
 	}
+
+	// The TODOs that did get an issue are numbered above before the scan fails for the rest
+	if fileErr != nil {
+		fmt.Printf("[ERROR]: %v\n", fileErr)
+		os.Exit(cmd.ExitCode(fileErr))
+	}
 }
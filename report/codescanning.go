@@ -0,0 +1,261 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jonathon-chew/Thoth/git"
+)
+
+// The parts of SARIF 2.1.0 used here, see https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string              `json:"id"`
+	Name                 string              `json:"name"`
+	ShortDescription     sarifMessage        `json:"shortDescription"`
+	FullDescription      sarifMessage        `json:"fullDescription"`
+	DefaultConfiguration sarifConfiguration  `json:"defaultConfiguration"`
+	Properties           sarifRuleProperties `json:"properties"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifRuleProperties struct {
+	Tags []string `json:"tags"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+	Properties          sarifProperties   `json:"properties"`
+}
+
+type sarifProperties struct {
+	Severity    string `json:"severity"`
+	Owner       string `json:"owner,omitempty"`
+	IssueNumber int    `json:"issueNumber,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+}
+
+// The parts of the Code Climate issue format GitLab reads for its code quality widget, see
+// https://github.com/codeclimate/platform/blob/master/spec/analyzers/SPEC.md#data-types
+type codeClimateIssue struct {
+	Type        string              `json:"type"`
+	CheckName   string              `json:"check_name"`
+	Description string              `json:"description"`
+	Categories  []string            `json:"categories"`
+	Severity    string              `json:"severity"`
+	Fingerprint string              `json:"fingerprint"`
+	Location    codeClimateLocation `json:"location"`
+}
+
+type codeClimateLocation struct {
+	Path  string           `json:"path"`
+	Lines codeClimateLines `json:"lines"`
+}
+
+type codeClimateLines struct {
+	Begin int `json:"begin"`
+	End   int `json:"end"`
+}
+
+// The key the fingerprint is stored under in SARIF, bumped if the way it's worked out ever changes
+const fingerprintVersion = "thoth/v1"
+
+// One rule per keyword, so TODO and FIXME can be filtered apart in code scanning
+func ruleID(keyword string) string {
+	return "thoth/" + strings.ToLower(keyword)
+}
+
+// The Code Climate severity for the keyword, keywords that aren't in severities are info
+func severity(keyword string, severities map[string]string) string {
+	if level, found := severities[keyword]; found {
+		return level
+	}
+	return "info"
+}
+
+// SARIF only has note, warning and error so the Code Climate severities are squashed into them
+func sarifLevel(severity string) string {
+	switch severity {
+	case "major":
+		return "warning"
+	case "critical", "blocker":
+		return "error"
+	}
+	return "note"
+}
+
+func message(entry Entry) string {
+	text := entry.Keyword + ": " + entry.Text
+	if entry.IssueNumber != 0 {
+		text += fmt.Sprintf(" (#%d)", entry.IssueNumber)
+	}
+	return text
+}
+
+// Every entry in order, whatever they were grouped by
+func flatten(groups []Group) []Entry {
+	var entries []Entry
+	for _, group := range groups {
+		entries = append(entries, group.Entries...)
+	}
+	return entries
+}
+
+// fingerprints are built from the file, keyword and normalised text rather than the line number, so a TODO keeps
+// its fingerprint when lines are added above it or it is numbered. Identical TODOs in one file are told apart by
+// which one they are from the top, which doesn't depend on how the report was grouped or sorted
+func fingerprints(entries []Entry) []string {
+	keys := make([]string, len(entries))
+	byKey := map[string][]int{}
+	for index, entry := range entries {
+		keys[index] = strings.Join([]string{filepath.ToSlash(entry.Path), entry.Keyword, git.NormalizeTitle(entry.Text)}, "\x00")
+		byKey[keys[index]] = append(byKey[keys[index]], index)
+	}
+
+	prints := make([]string, len(entries))
+	for key, indexes := range byKey {
+		sort.Slice(indexes, func(i, j int) bool { return entries[indexes[i]].Line < entries[indexes[j]].Line })
+
+		for occurrence, index := range indexes {
+			sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d", key, occurrence)))
+			prints[index] = hex.EncodeToString(sum[:16])
+		}
+	}
+	return prints
+}
+
+func writeSARIF(writer io.Writer, groups []Group, severities map[string]string) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "Thoth",
+			InformationURI: "https://github.com/jonathon-chew/Thoth",
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+
+	entries := flatten(groups)
+	prints := fingerprints(entries)
+
+	ruleIndexes := map[string]int{}
+	for index, entry := range entries {
+		id, level := ruleID(entry.Keyword), severity(entry.Keyword, severities)
+
+		ruleIndex, found := ruleIndexes[id]
+		if !found {
+			ruleIndex = len(run.Tool.Driver.Rules)
+			ruleIndexes[id] = ruleIndex
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+				ID:                   id,
+				Name:                 entry.Keyword,
+				ShortDescription:     sarifMessage{Text: entry.Keyword + " comment left in the code"},
+				FullDescription:      sarifMessage{Text: "A " + entry.Keyword + " comment marks work that still needs doing, thoth can turn it into an issue"},
+				DefaultConfiguration: sarifConfiguration{Level: sarifLevel(level)},
+				Properties:           sarifRuleProperties{Tags: []string{"maintainability", strings.ToLower(entry.Keyword)}},
+			})
+		}
+
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    id,
+			RuleIndex: ruleIndex,
+			Level:     sarifLevel(level),
+			Message:   sarifMessage{Text: message(entry)},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(entry.Path), URIBaseID: "%SRCROOT%"},
+				Region:           sarifRegion{StartLine: entry.Line, StartColumn: entry.Column},
+			}}},
+			PartialFingerprints: map[string]string{fingerprintVersion: prints[index]},
+			Properties:          sarifProperties{Severity: level, Owner: entry.Owner, IssueNumber: entry.IssueNumber},
+		})
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
+
+func writeCodeClimate(writer io.Writer, groups []Group, severities map[string]string) error {
+	entries := flatten(groups)
+	prints := fingerprints(entries)
+
+	// GitLab wants an array even when there is nothing in it
+	issues := []codeClimateIssue{}
+	for index, entry := range entries {
+		issues = append(issues, codeClimateIssue{
+			Type:        "issue",
+			CheckName:   ruleID(entry.Keyword),
+			Description: message(entry),
+			Categories:  []string{"Clarity"},
+			Severity:    severity(entry.Keyword, severities),
+			Fingerprint: prints[index],
+			Location: codeClimateLocation{
+				Path:  filepath.ToSlash(entry.Path),
+				Lines: codeClimateLines{Begin: entry.Line, End: entry.Line},
+			},
+		})
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(issues)
+}
@@ -0,0 +1,140 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Formats are the outputs Write can produce
+var Formats = []string{"table", "json", "sarif", "codeclimate", "markdown"}
+
+// Write prints the groups in the format. SARIF and Code Climate are flat lists of results so they ignore the grouping,
+// and are the only ones to use severities, a keyword to Code Climate severity map like config.Report.Severities
+func Write(writer io.Writer, format, groupedBy string, groups []Group, severities map[string]string) error {
+	switch format {
+	case "table", "":
+		return writeTable(writer, groups)
+	case "json":
+		return writeJSON(writer, groupedBy, groups)
+	case "sarif":
+		return writeSARIF(writer, groups, severities)
+	case "codeclimate":
+		return writeCodeClimate(writer, groups, severities)
+	case "markdown":
+		return writeMarkdown(writer, groupedBy, groups)
+	}
+
+	return fmt.Errorf("unknown format %q, use one of %v", format, Formats)
+}
+
+func total(groups []Group) int {
+	var count int
+	for _, group := range groups {
+		count += group.Count
+	}
+	return count
+}
+
+// The issue number, or - for a TODO without one
+func issueText(entry Entry) string {
+	if entry.IssueNumber == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("#%d", entry.IssueNumber)
+}
+
+// Who added the line, when (and how many days ago) and the short commit, or why that isn't known
+func blameColumns(entry Entry, now time.Time) []string {
+	if entry.Blame == nil {
+		return []string{"-", "not tracked", "-"}
+	}
+	if !entry.Blame.Committed() {
+		return []string{"-", notCommitted, "-"}
+	}
+
+	days := int(now.Sub(entry.Blame.Time).Hours() / 24)
+	return []string{entry.Blame.Author, fmt.Sprintf("%s (%d days)", entry.Blame.Time.Format("2006-01-02"), days), entry.Blame.Commit[:8]}
+}
+
+// The blame columns are only shown when something was blamed
+func anyBlamed(groups []Group) bool {
+	for _, group := range groups {
+		for _, entry := range group.Entries {
+			if entry.Blame != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func writeTable(writer io.Writer, groups []Group) error {
+	table := tabwriter.NewWriter(writer, 0, 4, 2, ' ', 0)
+	blamed, now := anyBlamed(groups), time.Now()
+
+	for _, group := range groups {
+		fmt.Fprintf(table, "%s (%d)\n", group.Name, group.Count)
+		for _, entry := range group.Entries {
+			fmt.Fprintf(table, "  %s:%d\t%s\t%s\t%s", entry.Path, entry.Line, entry.Keyword, issueText(entry), entry.Text)
+			if blamed {
+				fmt.Fprintf(table, "\t%s", strings.Join(blameColumns(entry, now), "\t"))
+			}
+			fmt.Fprintln(table)
+		}
+		fmt.Fprintln(table)
+	}
+
+	fmt.Fprintf(table, "%d found in %d groups\n", total(groups), len(groups))
+
+	return table.Flush()
+}
+
+func writeJSON(writer io.Writer, groupedBy string, groups []Group) error {
+	if groups == nil {
+		groups = []Group{}
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		Total     int     `json:"total"`
+		GroupedBy string  `json:"grouped_by"`
+		Groups    []Group `json:"groups"`
+	}{total(groups), groupedBy, groups})
+}
+
+func writeMarkdown(writer io.Writer, groupedBy string, groups []Group) error {
+	fmt.Fprintf(writer, "# TODO report\n\n%d found, grouped by %s\n", total(groups), groupedBy)
+
+	blamed, now := anyBlamed(groups), time.Now()
+
+	for _, group := range groups {
+		fmt.Fprintf(writer, "\n## %s (%d)\n\n", markdownCell(group.Name), group.Count)
+		if blamed {
+			fmt.Fprint(writer, "| Location | Keyword | Issue | Text | Author | Added | Commit |\n| --- | --- | --- | --- | --- | --- | --- |\n")
+		} else {
+			fmt.Fprint(writer, "| Location | Keyword | Issue | Text |\n| --- | --- | --- | --- |\n")
+		}
+
+		for _, entry := range group.Entries {
+			fmt.Fprintf(writer, "| `%s:%d` | %s | %s | %s |", entry.Path, entry.Line, entry.Keyword, issueText(entry), markdownCell(entry.Text))
+			if blamed {
+				for _, column := range blameColumns(entry, now) {
+					fmt.Fprintf(writer, " %s |", markdownCell(column))
+				}
+			}
+			fmt.Fprintln(writer)
+		}
+	}
+
+	return nil
+}
+
+// Pipes would end the cell early and new lines would end the row
+func markdownCell(text string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(text)
+}
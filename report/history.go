@@ -0,0 +1,121 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jonathon-chew/Thoth/git"
+	"github.com/jonathon-chew/Thoth/scanner"
+)
+
+// HistoryPoint is how many findings there were at a commit, in total and for each keyword
+type HistoryPoint struct {
+	git.Commit
+	Count    int            `json:"count"`
+	Keywords map[string]int `json:"keywords"`
+}
+
+// HistoryFormats are the outputs WriteHistory can produce, the chart is drawn by the command itself
+var HistoryFormats = []string{"chart", "sparkline", "csv", "json"}
+
+// History counts the findings at each commit, a few commits at a time, without checking any of them out
+// The keywords are what the scanner was built to look for
+func History(commits []git.Commit, scan *scanner.Scanner, keywords []string) ([]HistoryPoint, error) {
+	points := make([]HistoryPoint, len(commits))
+	errs := make([]error, len(commits))
+
+	slots := make(chan struct{}, runtime.NumCPU())
+	var workers sync.WaitGroup
+	for index, commit := range commits {
+		workers.Add(1)
+		slots <- struct{}{}
+
+		go func(index int, commit git.Commit) {
+			defer func() { <-slots; workers.Done() }()
+			points[index], errs[index] = countAt(commit, scan, keywords)
+		}(index, commit)
+	}
+	workers.Wait()
+
+	return points, errors.Join(errs...)
+}
+
+// git grep finds the lines with a keyword anywhere in them, the scanner then keeps the ones that are really TODOs
+func countAt(commit git.Commit, scan *scanner.Scanner, keywords []string) (HistoryPoint, error) {
+	point := HistoryPoint{Commit: commit, Keywords: map[string]int{}}
+	for _, keyword := range keywords {
+		point.Keywords[keyword] = 0
+	}
+
+	lines, err := git.GrepAt(commit.Hash, keywords)
+	if err != nil {
+		return point, fmt.Errorf("unable to count the TODOs in %s: %w", commit.Hash, err)
+	}
+
+	// The line above each match comes too, for thoth:ignore-next-line
+	type position struct {
+		path string
+		line int
+	}
+	texts := map[position]string{}
+	for _, line := range lines {
+		texts[position{line.Path, line.Line}] = line.Text
+	}
+
+	for _, line := range lines {
+		if !scanner.Wanted(line.Path) || scan.Ignored(line.Path) {
+			continue
+		}
+		if strings.Contains(texts[position{line.Path, line.Line - 1}], scanner.IgnoreNextLinePragma) {
+			continue
+		}
+		if finding, found := scan.ScanLine(line.Path, line.Line, line.Text); found {
+			point.Count++
+			point.Keywords[finding.Keyword]++
+		}
+	}
+
+	return point, nil
+}
+
+// WriteHistory writes the points as CSV, one row per commit with a column per keyword, or as JSON
+func WriteHistory(writer io.Writer, format string, points []HistoryPoint, keywords []string) error {
+	switch format {
+	case "csv":
+		table := csv.NewWriter(writer)
+		if err := table.Write(append([]string{"commit", "date", "total"}, keywords...)); err != nil {
+			return err
+		}
+
+		for _, point := range points {
+			row := []string{point.Hash, point.Time.Format(time.RFC3339), strconv.Itoa(point.Count)}
+			for _, keyword := range keywords {
+				row = append(row, strconv.Itoa(point.Keywords[keyword]))
+			}
+			if err := table.Write(row); err != nil {
+				return err
+			}
+		}
+
+		table.Flush()
+		return table.Error()
+
+	case "json":
+		if points == nil {
+			points = []HistoryPoint{}
+		}
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(points)
+	}
+
+	return fmt.Errorf("unknown format %q, use one of %v", format, HistoryFormats)
+}
@@ -0,0 +1,72 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jonathon-chew/Thoth/config"
+	"github.com/jonathon-chew/Thoth/scanner"
+)
+
+// The rules thoth check-todos can fail a finding on
+const (
+	RuleUnnumbered  = "unnumbered"
+	RuleClosedIssue = "closed-issue"
+	RuleExpired     = "expired"
+)
+
+// Violation is a finding that breaks the policy, and why
+type Violation struct {
+	scanner.Finding
+	Rule   string `json:"rule"`
+	Reason string `json:"reason"`
+}
+
+// IssueStates maps an issue number to open or closed, an issue that doesn't exist is missing from the map
+type IssueStates map[int]string
+
+// CheckPolicy returns the findings that break the policy, in the order of the findings, a finding can break more than one rule
+// states is only read when the policy checks closed issues, leave it nil to skip that rule
+func CheckPolicy(findings []scanner.Finding, policy config.Check, states IssueStates, now time.Time) []Violation {
+	var violations []Violation
+
+	for _, finding := range findings {
+		if policy.Unnumbered && finding.IssueNumber == 0 {
+			violations = append(violations, Violation{finding, RuleUnnumbered, "has no issue number"})
+		}
+
+		if policy.ClosedIssues && states != nil && finding.IssueNumber != 0 {
+			switch state, found := states[finding.IssueNumber]; {
+			case !found:
+				violations = append(violations, Violation{finding, RuleClosedIssue, fmt.Sprintf("issue #%d doesn't exist", finding.IssueNumber)})
+			case state == "closed":
+				violations = append(violations, Violation{finding, RuleClosedIssue, fmt.Sprintf("issue #%d is closed", finding.IssueNumber)})
+			}
+		}
+
+		if policy.Expired && finding.Due != "" {
+			due, err := scanner.ParseDue(finding.Due)
+			switch {
+			case err != nil:
+				violations = append(violations, Violation{finding, RuleExpired, err.Error()})
+			case !now.Before(due.AddDate(0, 0, 1)):
+				violations = append(violations, Violation{finding, RuleExpired, "was due on " + finding.Due})
+			}
+		}
+	}
+
+	return violations
+}
+
+// WriteViolations lists the violations as a table, one line each with the file and line, the rule and why
+func WriteViolations(writer io.Writer, violations []Violation) error {
+	table := tabwriter.NewWriter(writer, 0, 4, 2, ' ', 0)
+
+	for _, violation := range violations {
+		fmt.Fprintf(table, "  %s:%d\t%s\t%s\t%s: %s\n", violation.Path, violation.Line, violation.Rule, violation.Reason, violation.Keyword, violation.Text)
+	}
+
+	return table.Flush()
+}
@@ -0,0 +1,223 @@
+package report
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jonathon-chew/Thoth/git"
+	"github.com/jonathon-chew/Thoth/scanner"
+)
+
+// Entry is a finding along with what git blame knows about its line, Blame is nil when the file isn't tracked
+type Entry struct {
+	scanner.Finding
+	Blame *git.BlameLine `json:"blame,omitempty"`
+}
+
+// Group is the entries sharing a file, keyword, owner or age
+type Group struct {
+	Name    string  `json:"name"`
+	Count   int     `json:"count"`
+	Entries []Entry `json:"findings"`
+}
+
+// GroupKeys are what GroupBy can group on
+var GroupKeys = []string{"file", "keyword", "owner", "age"}
+
+// Age groups, youngest first, a TODO goes in the first one it is younger than
+var ageGroups = []struct {
+	name  string
+	limit time.Duration
+}{
+	{"less than a week", 7 * 24 * time.Hour},
+	{"less than a month", 30 * 24 * time.Hour},
+	{"less than 3 months", 90 * 24 * time.Hour},
+	{"less than a year", 365 * 24 * time.Hour},
+	{"more than a year", 0},
+}
+
+const (
+	notCommitted = "not committed"
+	noOwner      = "no owner"
+)
+
+// NewEntries wraps the findings without blaming them, for when the age isn't needed
+func NewEntries(findings []scanner.Finding) []Entry {
+	entries := make([]Entry, len(findings))
+	for index, finding := range findings {
+		entries[index] = Entry{Finding: finding}
+	}
+	return entries
+}
+
+// Blame runs git blame once per file with findings, a few files at a time, and attaches the line's blame to each entry
+// Files git can't blame (untracked, or not in a repository at all) are left without
+func Blame(entries []Entry) {
+	byPath := map[string][]int{}
+	for index, entry := range entries {
+		byPath[entry.Path] = append(byPath[entry.Path], index)
+	}
+
+	slots := make(chan struct{}, runtime.NumCPU())
+	var workers sync.WaitGroup
+	for path, indexes := range byPath {
+		workers.Add(1)
+		slots <- struct{}{}
+
+		go func(path string, indexes []int) {
+			defer func() { <-slots; workers.Done() }()
+
+			lines, err := git.Blame(path)
+			if err != nil {
+				return
+			}
+
+			// Each goroutine has its own indexes so nothing is written twice
+			for _, index := range indexes {
+				if line := entries[index].Line; line <= len(lines) {
+					blame := lines[line-1]
+					entries[index].Blame = &blame
+				}
+			}
+		}(path, indexes)
+	}
+	workers.Wait()
+}
+
+// Age is how long ago the line was committed, false when it hasn't been
+func (entry Entry) Age(now time.Time) (time.Duration, bool) {
+	if entry.Blame == nil || !entry.Blame.Committed() {
+		return 0, false
+	}
+	return now.Sub(entry.Blame.Time), true
+}
+
+// GroupBy puts the entries into groups on the key, one of GroupKeys. Age needs the entries to have been blamed
+// Age groups run youngest to oldest, the others are sorted by name with the entries without one last
+func GroupBy(entries []Entry, key string, now time.Time) ([]Group, error) {
+	var name func(Entry) string
+
+	switch key {
+	case "file":
+		name = func(entry Entry) string { return entry.Path }
+	case "keyword":
+		name = func(entry Entry) string { return entry.Keyword }
+	case "owner":
+		name = func(entry Entry) string {
+			if entry.Owner == "" {
+				return noOwner
+			}
+			return entry.Owner
+		}
+	case "age":
+		name = func(entry Entry) string { return ageGroup(entry, now) }
+	default:
+		return nil, fmt.Errorf("can't group by %q, use one of %v", key, GroupKeys)
+	}
+
+	var groups []Group
+	positions := map[string]int{}
+	for _, entry := range entries {
+		groupName := name(entry)
+
+		position, found := positions[groupName]
+		if !found {
+			position = len(groups)
+			positions[groupName] = position
+			groups = append(groups, Group{Name: groupName})
+		}
+
+		groups[position].Entries = append(groups[position].Entries, entry)
+		groups[position].Count++
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		orderI, orderJ := groupOrder(key, groups[i].Name), groupOrder(key, groups[j].Name)
+		if orderI != orderJ {
+			return orderI < orderJ
+		}
+		return groups[i].Name < groups[j].Name
+	})
+
+	return groups, nil
+}
+
+func ageGroup(entry Entry, now time.Time) string {
+	age, committed := entry.Age(now)
+	if !committed {
+		return notCommitted
+	}
+
+	for _, group := range ageGroups {
+		if group.limit == 0 || age < group.limit {
+			return group.name
+		}
+	}
+	return ageGroups[len(ageGroups)-1].name
+}
+
+// Age groups keep their own order, everything else is by name with the catch all groups at the end
+func groupOrder(key, name string) int {
+	if key == "age" {
+		for index, group := range ageGroups {
+			if group.name == name {
+				return index
+			}
+		}
+		return len(ageGroups)
+	}
+
+	if name == noOwner {
+		return 1
+	}
+	return 0
+}
+
+// SortByAge puts the oldest committed entries first, lines not committed yet (or not blamed) go last
+func SortByAge(entries []Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		committedI := entries[i].Blame != nil && entries[i].Blame.Committed()
+		committedJ := entries[j].Blame != nil && entries[j].Blame.Committed()
+
+		if committedI != committedJ {
+			return committedI
+		}
+		return committedI && entries[i].Blame.Time.Before(entries[j].Blame.Time)
+	})
+}
+
+// OlderThan keeps the committed entries that were committed more than age ago
+func OlderThan(entries []Entry, age time.Duration, now time.Time) []Entry {
+	var older []Entry
+	for _, entry := range entries {
+		if entryAge, committed := entry.Age(now); committed && entryAge > age {
+			older = append(older, entry)
+		}
+	}
+	return older
+}
+
+// ParseAge reads an age such as 90d, 12w or 1y, anything else is read as a Go duration such as 36h
+func ParseAge(value string) (time.Duration, error) {
+	units := map[byte]time.Duration{'d': 24 * time.Hour, 'w': 7 * 24 * time.Hour, 'y': 365 * 24 * time.Hour}
+
+	if value != "" {
+		if unit, found := units[value[len(value)-1]]; found {
+			count, err := strconv.Atoi(value[:len(value)-1])
+			if err != nil || count < 0 {
+				return 0, fmt.Errorf("%q isn't an age, use something like 90d, 12w or 1y", value)
+			}
+			return time.Duration(count) * unit, nil
+		}
+	}
+
+	age, err := time.ParseDuration(value)
+	if err != nil || age < 0 {
+		return 0, fmt.Errorf("%q isn't an age, use something like 90d, 12w or 1y", value)
+	}
+	return age, nil
+}
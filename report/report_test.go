@@ -0,0 +1,222 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jonathon-chew/Thoth/config"
+	"github.com/jonathon-chew/Thoth/git"
+	"github.com/jonathon-chew/Thoth/scanner"
+)
+
+func blamed(finding scanner.Finding, commit string, at time.Time) Entry {
+	return Entry{Finding: finding, Blame: &git.BlameLine{Commit: commit, Time: at}}
+}
+
+func TestGroupBy(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	committed := "1111111111111111111111111111111111111111"
+
+	entries := []Entry{
+		blamed(scanner.Finding{Path: "b.go", Keyword: "TODO", Owner: "sam"}, committed, now.Add(-2*24*time.Hour)),
+		blamed(scanner.Finding{Path: "a.go", Keyword: "FIXME"}, committed, now.Add(-400*24*time.Hour)),
+		blamed(scanner.Finding{Path: "a.go", Keyword: "TODO", Owner: "alex"}, "0000000000000000000000000000000000000000", now),
+		{Finding: scanner.Finding{Path: "c.go", Keyword: "TODO", Owner: "sam"}},
+	}
+
+	tests := []struct {
+		key  string
+		want []string
+	}{
+		{"file", []string{"a.go:2", "b.go:1", "c.go:1"}},
+		{"keyword", []string{"FIXME:1", "TODO:3"}},
+		{"owner", []string{"alex:1", "sam:2", "no owner:1"}},
+		{"age", []string{"less than a week:1", "more than a year:1", "not committed:2"}},
+	}
+
+	for _, test := range tests {
+		groups, err := GroupBy(entries, test.key, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []string
+		for _, group := range groups {
+			got = append(got, fmt.Sprintf("%s:%d", group.Name, group.Count))
+		}
+		if len(got) != len(test.want) {
+			t.Errorf("%s: got %v, want %v", test.key, got, test.want)
+			continue
+		}
+		for index := range got {
+			if got[index] != test.want[index] {
+				t.Errorf("%s: got %v, want %v", test.key, got, test.want)
+				break
+			}
+		}
+	}
+
+	if _, err := GroupBy(entries, "colour", now); err == nil {
+		t.Error("grouping by an unknown key should fail")
+	}
+}
+
+func TestAgeFilterAndSort(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	committed := "1111111111111111111111111111111111111111"
+
+	entries := []Entry{
+		blamed(scanner.Finding{Path: "new.go"}, committed, now.Add(-10*24*time.Hour)),
+		{Finding: scanner.Finding{Path: "untracked.go"}},
+		blamed(scanner.Finding{Path: "oldest.go"}, committed, now.Add(-400*24*time.Hour)),
+		blamed(scanner.Finding{Path: "old.go"}, committed, now.Add(-100*24*time.Hour)),
+	}
+
+	SortByAge(entries)
+	var order []string
+	for _, entry := range entries {
+		order = append(order, entry.Path)
+	}
+	if fmt.Sprint(order) != "[oldest.go old.go new.go untracked.go]" {
+		t.Errorf("sorted by age got %v", order)
+	}
+
+	age, err := ParseAge("90d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	older := OlderThan(entries, age, now)
+	if len(older) != 2 || older[0].Path != "oldest.go" || older[1].Path != "old.go" {
+		t.Errorf("older than 90d got %v", older)
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	day := 24 * time.Hour
+	for value, want := range map[string]time.Duration{"90d": 90 * day, "2w": 14 * day, "1y": 365 * day, "36h": 36 * time.Hour} {
+		if got, err := ParseAge(value); err != nil || got != want {
+			t.Errorf("ParseAge(%q) = %v, %v, want %v", value, got, err, want)
+		}
+	}
+
+	for _, value := range []string{"", "d", "-3d", "soon", "1x"} {
+		if _, err := ParseAge(value); err == nil {
+			t.Errorf("ParseAge(%q) should fail", value)
+		}
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	groups := []Group{{Name: "a.go", Count: 2, Entries: []Entry{
+		{Finding: scanner.Finding{Path: "cmd/a.go", Line: 3, Column: 4, Keyword: "TODO", Text: "one", IssueNumber: 7}},
+		{Finding: scanner.Finding{Path: "cmd/a.go", Line: 9, Column: 1, Keyword: "FIXME", Text: "two"}},
+	}}}
+
+	var output bytes.Buffer
+	if err := Write(&output, "sarif", "file", groups, map[string]string{"FIXME": "critical"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(output.Bytes(), &log); err != nil {
+		t.Fatalf("not valid json: %v\n%s", err, output.String())
+	}
+
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || len(log.Runs[0].Tool.Driver.Rules) != 2 || len(log.Runs[0].Results) != 2 {
+		t.Fatalf("unexpected SARIF %s", output.String())
+	}
+
+	result := log.Runs[0].Results[0]
+	if result.RuleID != "thoth/todo" || result.Message.Text != "TODO: one (#7)" || result.Locations[0].PhysicalLocation.Region.StartLine != 3 {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if result.Level != "note" || log.Runs[0].Results[1].Level != "error" || log.Runs[0].Results[1].RuleIndex != 1 {
+		t.Errorf("TODO should be a note and FIXME an error, got %+v", log.Runs[0].Results)
+	}
+	if result.PartialFingerprints[fingerprintVersion] == "" {
+		t.Errorf("result has no fingerprint %+v", result)
+	}
+}
+
+func TestWriteCodeClimate(t *testing.T) {
+	groups := []Group{{Name: "a.go", Count: 3, Entries: []Entry{
+		{Finding: scanner.Finding{Path: "a.go", Line: 20, Keyword: "FIXME", Text: "same"}},
+		{Finding: scanner.Finding{Path: "a.go", Line: 5, Keyword: "FIXME", Text: "same"}},
+		{Finding: scanner.Finding{Path: "a.go", Line: 9, Keyword: "TODO", Text: "other"}},
+	}}}
+
+	var output bytes.Buffer
+	if err := Write(&output, "codeclimate", "file", groups, map[string]string{"FIXME": "major"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var issues []codeClimateIssue
+	if err := json.Unmarshal(output.Bytes(), &issues); err != nil {
+		t.Fatalf("not valid json: %v\n%s", err, output.String())
+	}
+
+	if len(issues) != 3 || issues[0].Severity != "major" || issues[2].Severity != "info" || issues[0].Location.Lines.Begin != 20 {
+		t.Fatalf("unexpected issues %s", output.String())
+	}
+	if issues[0].Fingerprint == issues[1].Fingerprint {
+		t.Error("identical TODOs in one file should have different fingerprints")
+	}
+
+	// Moving the TODOs down the file keeps their fingerprints, the first from the top is still the first
+	moved := []Entry{groups[0].Entries[1], groups[0].Entries[0]}
+	moved[0].Line, moved[1].Line = 50, 60
+	prints := fingerprints(moved)
+	if prints[0] != issues[1].Fingerprint || prints[1] != issues[0].Fingerprint {
+		t.Error("fingerprints changed when the lines moved")
+	}
+}
+
+func TestWriteHistoryCSV(t *testing.T) {
+	points := []HistoryPoint{
+		{Commit: git.Commit{Hash: "aaa", Time: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)}, Count: 3, Keywords: map[string]int{"TODO": 2, "FIXME": 1}},
+		{Commit: git.Commit{Hash: "bbb", Time: time.Date(2026, 1, 12, 0, 0, 0, 0, time.UTC)}, Count: 1, Keywords: map[string]int{"TODO": 1}},
+	}
+
+	var output bytes.Buffer
+	if err := WriteHistory(&output, "csv", points, []string{"TODO", "FIXME"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "commit,date,total,TODO,FIXME\naaa,2026-01-05T00:00:00Z,3,2,1\nbbb,2026-01-12T00:00:00Z,1,1,0\n"
+	if output.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", output.String(), want)
+	}
+}
+
+func TestCheckPolicy(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.Local)
+	findings := []scanner.Finding{
+		{Path: "a.go", Line: 1, Text: "no number"},
+		{Path: "a.go", Line: 2, IssueNumber: 3, Text: "open issue"},
+		{Path: "a.go", Line: 3, IssueNumber: 4, Text: "closed issue"},
+		{Path: "a.go", Line: 4, IssueNumber: 5, Text: "missing issue"},
+		{Path: "a.go", Line: 5, IssueNumber: 3, Due: "2026-06-01", Text: "due today"},
+		{Path: "a.go", Line: 6, IssueNumber: 3, Due: "2026-05-31", Text: "due yesterday"},
+		{Path: "a.go", Line: 7, IssueNumber: 3, Due: "next week", Text: "unreadable date"},
+	}
+	states := IssueStates{3: "open", 4: "closed"}
+	policy := config.Default().Check
+
+	var got []string
+	for _, violation := range CheckPolicy(findings, policy, states, now) {
+		got = append(got, fmt.Sprintf("%d:%s", violation.Line, violation.Rule))
+	}
+	want := "[1:unnumbered 3:closed-issue 4:closed-issue 6:expired 7:expired]"
+	if fmt.Sprint(got) != want {
+		t.Errorf("got %v, want %s", got, want)
+	}
+
+	// Without states the closed issue rule is skipped, and turned off rules don't report anything
+	policy.Unnumbered = false
+	if violations := CheckPolicy(findings, policy, nil, now); len(violations) != 2 {
+		t.Errorf("got %+v, want just the two expired", violations)
+	}
+}
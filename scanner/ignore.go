@@ -0,0 +1,169 @@
+package scanner
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// IgnoreFileName lists paths to leave out of every scan, written like a .gitignore, in the folder scanned
+const IgnoreFileName = ".thothignore"
+
+// Ignore is a parsed IgnoreFileName, the nil Ignore leaves nothing out
+type Ignore struct {
+	rules []ignoreRule
+}
+
+type ignoreRule struct {
+	pattern *regexp.Regexp
+	negate  bool // !pattern puts back what an earlier pattern left out
+	dirOnly bool // pattern/ only matches folders
+}
+
+// LoadIgnore reads IgnoreFileName from the folder, no file at all leaves nothing out
+func LoadIgnore(directory string) (*Ignore, error) {
+	contents, err := os.ReadFile(filepath.Join(directory, IgnoreFileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseIgnore(string(contents)), nil
+}
+
+// ParseIgnore reads patterns in .gitignore syntax: # comments, ! to put a path back, a trailing / for folders only,
+// a / at the start or in the middle to match from the root only, and *, ?, [abc] and ** wildcards
+func ParseIgnore(contents string) *Ignore {
+	ignore := &Ignore{}
+
+	for _, line := range strings.Split(contents, "\n") {
+		line = strings.TrimRight(line, "\r")
+
+		// Trailing spaces are dropped unless the last one is escaped
+		if !strings.HasSuffix(line, `\ `) {
+			line = strings.TrimRight(line, " \t")
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var rule ignoreRule
+		switch {
+		case strings.HasPrefix(line, "!"):
+			rule.negate = true
+			line = line[1:]
+		case strings.HasPrefix(line, `\!`), strings.HasPrefix(line, `\#`):
+			line = line[1:]
+		}
+
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+
+		anchored := strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+		if line == "" {
+			continue
+		}
+
+		expression := globExpression(line)
+		if !anchored {
+			expression = "(?:.*/)?" + expression
+		}
+
+		// A pattern git would reject, like an unclosed [, can't match anything so it's left out
+		pattern, err := regexp.Compile("^" + expression + "$")
+		if err != nil {
+			continue
+		}
+		rule.pattern = pattern
+
+		ignore.rules = append(ignore.rules, rule)
+	}
+
+	return ignore
+}
+
+// Turns a glob into a regular expression, * and ? don't match a / but ** on its own between slashes matches any number of folders
+func globExpression(glob string) string {
+	var expression strings.Builder
+
+	for index := 0; index < len(glob); index++ {
+		character := glob[index]
+		wholeSegment := index == 0 || glob[index-1] == '/'
+
+		switch {
+		case strings.HasPrefix(glob[index:], "**/") && wholeSegment:
+			expression.WriteString("(?:.*/)?")
+			index += 2
+		case glob[index:] == "**" && wholeSegment:
+			expression.WriteString(".*")
+			index++
+		case character == '*':
+			expression.WriteString("[^/]*")
+		case character == '?':
+			expression.WriteString("[^/]")
+		case character == '\\' && index+1 < len(glob):
+			index++
+			expression.WriteString(regexp.QuoteMeta(glob[index : index+1]))
+		case character == '[':
+			end := strings.IndexByte(glob[index+1:], ']')
+			if end == -1 {
+				expression.WriteString(`\[`)
+				continue
+			}
+			class := glob[index+1 : index+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			expression.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			index += end + 1
+		default:
+			expression.WriteString(regexp.QuoteMeta(glob[index : index+1]))
+		}
+	}
+
+	return expression.String()
+}
+
+// Match is true when the path, relative to the folder the ignore file is in, is left out by the last pattern matching it
+// It only looks at the path itself, see Ignored for a file inside a folder that is left out
+func (ignore *Ignore) Match(path string, isDir bool) bool {
+	if ignore == nil {
+		return false
+	}
+
+	path = strings.TrimPrefix(filepath.ToSlash(filepath.Clean(path)), "./")
+
+	var matched bool
+	for _, rule := range ignore.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if rule.pattern.MatchString(path) {
+			matched = !rule.negate
+		}
+	}
+	return matched
+}
+
+// Ignored is true when the file or any folder it is in is left out, like git a file can't be put back once its folder is
+func (ignore *Ignore) Ignored(path string) bool {
+	if ignore == nil {
+		return false
+	}
+
+	parts := strings.Split(strings.TrimPrefix(filepath.ToSlash(filepath.Clean(path)), "./"), "/")
+	for end := 1; end < len(parts); end++ {
+		if ignore.Match(strings.Join(parts[:end], "/"), true) {
+			return true
+		}
+	}
+	return ignore.Match(path, false)
+}
@@ -0,0 +1,73 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIgnoreMatch(t *testing.T) {
+	ignore := ParseIgnore(`# fixtures and generated code
+testdata
+/build/
+*.pb.go
+!keep.pb.go
+docs/**/draft-?.md
+**/vendor/**
+file[0-9].txt
+\#notes
+`)
+
+	cases := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"testdata", true, true},
+		{"pkg/testdata", true, true},
+		{"build", true, true},
+		{"build", false, false},
+		{"pkg/build", true, false},
+		{"api/user.pb.go", false, true},
+		{"api/keep.pb.go", false, false},
+		{"docs/draft-1.md", false, true},
+		{"docs/a/b/draft-2.md", false, true},
+		{"docs/draft-10.md", false, false},
+		{"third/vendor/lib.go", false, true},
+		{"file3.txt", false, true},
+		{"filex.txt", false, false},
+		{"#notes", false, true},
+		{"main.go", false, false},
+	}
+	for _, test := range cases {
+		if got := ignore.Match(test.path, test.isDir); got != test.want {
+			t.Errorf("Match(%q, %v) = %v, want %v", test.path, test.isDir, got, test.want)
+		}
+	}
+
+	if !ignore.Ignored("pkg/testdata/fixture.go") || !ignore.Ignored("./build/out.go") || ignore.Ignored("cmd/main.go") {
+		t.Error("a file wasn't left out along with its folder")
+	}
+
+	var none *Ignore
+	if none.Match("anything", false) || none.Ignored("anything") {
+		t.Error("the nil Ignore left something out")
+	}
+}
+
+func TestLoadIgnore(t *testing.T) {
+	root := t.TempDir()
+
+	ignore, err := LoadIgnore(root)
+	if err != nil || ignore != nil {
+		t.Fatalf("a missing ignore file should leave nothing out, got %v %v", ignore, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, IgnoreFileName), []byte("*.md\r\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ignore, err = LoadIgnore(root)
+	if err != nil || !ignore.Ignored("README.md") {
+		t.Errorf("the ignore file wasn't read: %v", err)
+	}
+}
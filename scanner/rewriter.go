@@ -0,0 +1,117 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Rewriter collects issue numbers (or IgnorePragma) for findings and writes them in front of the keyword, reading and writing each file once
+type Rewriter struct {
+	edits map[string][]edit
+}
+
+type edit struct {
+	finding Finding
+	insert  string // Written in front of the keyword
+}
+
+// NewRewriter makes an empty Rewriter
+func NewRewriter() *Rewriter {
+	return &Rewriter{edits: map[string][]edit{}}
+}
+
+// Number queues the finding to become (#number) KEYWORD: ..., findings that already have a number are ignored
+func (rewriter *Rewriter) Number(finding Finding, number int) {
+	if finding.IssueNumber != 0 || number < 1 {
+		return
+	}
+	rewriter.edits[finding.Path] = append(rewriter.edits[finding.Path], edit{finding: finding, insert: fmt.Sprintf("(#%d) ", number)})
+}
+
+// Ignore queues the finding to become thoth:ignore KEYWORD: ..., so it is never scanned again
+func (rewriter *Rewriter) Ignore(finding Finding) {
+	rewriter.edits[finding.Path] = append(rewriter.edits[finding.Path], edit{finding: finding, insert: IgnorePragma + " "})
+}
+
+// NumberLine puts (#number) in front of the keyword at the 1 based column
+func NumberLine(line string, column, number int) string {
+	return insertAt(line, column, fmt.Sprintf("(#%d) ", number))
+}
+
+func insertAt(line string, column int, text string) string {
+	index := column - 1
+	if index < 0 || index > len(line) {
+		return line
+	}
+	return line[:index] + text + line[index:]
+}
+
+// Apply writes the queued numbers and pragmas into the files and returns how many lines were changed
+// A line that changed since it was scanned is left alone and reported in the error, the rest are still written
+func (rewriter *Rewriter) Apply() (int, error) {
+	read := func(path string) (string, error) {
+		contents, err := os.ReadFile(path)
+		return string(contents), err
+	}
+
+	write := func(path, contents string) error {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, []byte(contents), info.Mode().Perm())
+	}
+
+	return rewriter.ApplyTo(read, write)
+}
+
+// ApplyTo is Apply for files kept somewhere other than the working tree, such as the staged versions (see git.FileAt and git.WriteStaged)
+func (rewriter *Rewriter) ApplyTo(read func(path string) (string, error), write func(path, contents string) error) (int, error) {
+	var numbered int
+	var skipped []string
+
+	paths := make([]string, 0, len(rewriter.edits))
+	for path := range rewriter.edits {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		contents, err := read(path)
+		if err != nil {
+			return numbered, err
+		}
+		lines := strings.Split(contents, "\n")
+
+		var changed bool
+		for _, edit := range rewriter.edits[path] {
+			line := edit.finding.Line
+			if line > len(lines) || lines[line-1] != edit.finding.Source {
+				skipped = append(skipped, fmt.Sprintf("%s line %d (%s)", path, line, strings.TrimSpace(edit.insert)))
+				continue
+			}
+
+			lines[line-1] = insertAt(lines[line-1], edit.finding.Column, edit.insert)
+			numbered++
+			changed = true
+		}
+
+		if !changed {
+			continue
+		}
+
+		if err := write(path, strings.Join(lines, "\n")); err != nil {
+			return numbered, err
+		}
+	}
+
+	rewriter.edits = map[string][]edit{}
+
+	if len(skipped) > 0 {
+		return numbered, fmt.Errorf("these lines changed during the scan so weren't changed: %s", strings.Join(skipped, ", "))
+	}
+
+	return numbered, nil
+}
@@ -0,0 +1,393 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	utils "github.com/jonathon-chew/Thoth/Utils"
+)
+
+// Finding is a TODO (or another keyword) found in a file
+type Finding struct {
+	Path        string `json:"path"`
+	Line        int    `json:"line"`               // 1 based
+	Column      int    `json:"column"`             // 1 based byte offset of the keyword
+	Keyword     string `json:"keyword"`            // TODO unless other keywords were asked for
+	Text        string `json:"text"`               // What comes after the keyword and its colon
+	IssueNumber int    `json:"issue_number"`       // The (#N) in front of the keyword, 0 when it has no issue yet
+	Owner       string `json:"owner"`              // bob in TODO(bob)
+	Priority    string `json:"priority,omitempty"` // p1 in TODO(p1) or TODO(priority:p1), always lower case
+	Due         string `json:"due,omitempty"`      // 2026-01-31 in TODO(due:2026-01-31) or TODO(by:2026-01-31), see ParseDue
+	Source      string `json:"-"`                  // The whole line as it is in the file, so a rewrite can check it hasn't changed
+}
+
+// LineFilter limits a scan to some lines, git.ChangedLines is one
+type LineFilter interface {
+	HasFile(path string) bool
+	Contains(path string, line int) bool
+}
+
+// Options for a Scanner, the zero value looks for TODO in every line
+type Options struct {
+	Keywords []string   // Defaults to TODO
+	Lines    LineFilter // Leave nil to scan every line
+	Workers  int        // Files read at once by ScanTree, defaults to the number of CPUs
+	Ignore   *Ignore    // Paths left out, ScanTree reads IgnoreFileName from the folder it scans when this is nil
+}
+
+// Scanner finds keyword lines such as a TODO, TODO(bob) or (#12) TODO comment, where the keyword is followed by a colon
+type Scanner struct {
+	options Options
+	pattern *regexp.Regexp
+	markers [][]byte
+}
+
+// The known files to ignore!
+var (
+	unwantedFiles      = []string{".localized", ".DS_Store", ".gitignore"}
+	unwantedExtentions = []string{".app", ".exe", ".elf", ".md"}
+)
+
+// Pragmas that stop a TODO being found: IgnorePragma on its line, e.g. "// thoth:ignore TODO: an example",
+// or IgnoreNextLinePragma on the line above it
+const (
+	IgnorePragma         = "thoth:ignore"
+	IgnoreNextLinePragma = "thoth:ignore-next-line"
+)
+
+// New builds a Scanner for the options
+func New(options Options) *Scanner {
+	if len(options.Keywords) == 0 {
+		options.Keywords = []string{"TODO"}
+	}
+	if options.Workers < 1 {
+		options.Workers = runtime.NumCPU()
+	}
+
+	var quoted []string
+	var markers [][]byte
+	for _, keyword := range options.Keywords {
+		quoted = append(quoted, regexp.QuoteMeta(keyword))
+		markers = append(markers, []byte(keyword))
+	}
+
+	return &Scanner{
+		options: options,
+		pattern: regexp.MustCompile(`(?:\(#(\d+)\) )?\b(` + strings.Join(quoted, "|") + `)(?:\(([^)]*)\))?: `),
+		markers: markers,
+	}
+}
+
+// ScanLine returns the finding on the line, if there is one. Lines with IgnorePragma on them are left out
+func (scanner *Scanner) ScanLine(path string, lineNumber int, line string) (Finding, bool) {
+	match := scanner.pattern.FindStringSubmatchIndex(line)
+	if match == nil || ignoresLine(line) {
+		return Finding{}, false
+	}
+
+	finding := Finding{
+		Path:    path,
+		Line:    lineNumber,
+		Column:  match[4] + 1,
+		Keyword: line[match[4]:match[5]],
+		Text:    strings.TrimSpace(line[match[1]:]),
+		Source:  line,
+	}
+
+	if match[2] != -1 {
+		finding.IssueNumber, _ = strconv.Atoi(line[match[2]:match[3]])
+	}
+
+	if match[6] != -1 {
+		meta := line[match[6]:match[7]]
+		finding.Owner = owner(meta)
+		finding.Priority = priority(meta)
+		finding.Due = metaValue(meta, "due", "by")
+	}
+
+	return finding, true
+}
+
+// Priorities are written p0 (the most urgent) to p9
+var priorityPattern = regexp.MustCompile(`^[pP][0-9]$`)
+
+// The owner is the first part of TODO(...) that isn't a key:value pair or a priority, with any leading @ dropped
+func owner(meta string) string {
+	for _, part := range strings.Split(meta, ",") {
+		if part = strings.TrimSpace(part); part != "" && !strings.Contains(part, ":") && !priorityPattern.MatchString(part) {
+			return strings.TrimPrefix(part, "@")
+		}
+	}
+	return ""
+}
+
+// The priority is a part of TODO(...) such as p1, or the value of priority:
+func priority(meta string) string {
+	for _, part := range strings.Split(meta, ",") {
+		if part = strings.TrimSpace(part); priorityPattern.MatchString(part) {
+			return strings.ToLower(part)
+		}
+	}
+	return strings.ToLower(metaValue(meta, "priority"))
+}
+
+// IgnoreNextLinePragma starts with IgnorePragma but only ignores the line after it
+func ignoresLine(line string) bool {
+	return strings.Contains(strings.ReplaceAll(line, IgnoreNextLinePragma, ""), IgnorePragma)
+}
+
+// Ignored is true for the paths the scanner's ignore file leaves out
+func (scanner *Scanner) Ignored(path string) bool {
+	return scanner.options.Ignore.Ignored(path)
+}
+
+// The value of the first key:value part of TODO(...) with one of the keys
+func metaValue(meta string, keys ...string) string {
+	for _, part := range strings.Split(meta, ",") {
+		key, value, found := strings.Cut(part, ":")
+		if found && slices.Contains(keys, strings.ToLower(strings.TrimSpace(key))) {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// ParseDue reads a due date written as 2026-01-31, the TODO is due by the end of that day
+func ParseDue(due string) (time.Time, error) {
+	date, err := time.ParseInLocation("2006-01-02", due, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q isn't a date, write it as 2026-01-31", due)
+	}
+	return date, nil
+}
+
+// ScanReader reads everything from the reader as the contents of the file at path, nothing is found when Ignore leaves it out
+func (scanner *Scanner) ScanReader(path string, reader io.Reader) ([]Finding, error) {
+	contents, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if scanner.Ignored(path) {
+		return nil, nil
+	}
+
+	return scanner.scanContents(path, contents), nil
+}
+
+func (scanner *Scanner) scanContents(path string, contents []byte) []Finding {
+	if !slices.ContainsFunc(scanner.markers, func(marker []byte) bool { return bytes.Contains(contents, marker) }) {
+		return nil
+	}
+
+	var findings []Finding
+
+	// Split on \n alone so the lines are exactly what the Rewriter will find in the file
+	lines := strings.Split(string(contents), "\n")
+	for index, line := range lines {
+		if scanner.options.Lines != nil && !scanner.options.Lines.Contains(path, index+1) {
+			continue
+		}
+
+		if index > 0 && strings.Contains(lines[index-1], IgnoreNextLinePragma) {
+			continue
+		}
+
+		if finding, found := scanner.ScanLine(path, index+1, line); found {
+			findings = append(findings, finding)
+		}
+	}
+
+	return findings
+}
+
+// ScanDiff finds the keyword lines added in a unified diff, with their line numbers in the new file
+func (scanner *Scanner) ScanDiff(reader io.Reader) ([]Finding, error) {
+	var findings []Finding
+
+	var path string
+	var lineNumber int
+	var previous string // The line above in the new file, when the diff shows it
+	lines := bufio.NewScanner(reader)
+	lines.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for lines.Scan() {
+		line := lines.Text()
+
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			path = strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+			if path == "/dev/null" || scanner.Ignored(path) {
+				path = ""
+			}
+		case strings.HasPrefix(line, "@@ "):
+			// @@ -a,b +c,d @@ starts the new file at line c
+			fields := strings.Fields(line)
+			if len(fields) < 3 {
+				continue
+			}
+			start, _, _ := strings.Cut(strings.TrimPrefix(fields[2], "+"), ",")
+			lineNumber, _ = strconv.Atoi(start)
+			previous = ""
+		case path == "":
+		case strings.HasPrefix(line, "+"):
+			if scanner.options.Lines == nil || scanner.options.Lines.Contains(path, lineNumber) {
+				if finding, found := scanner.ScanLine(path, lineNumber, line[1:]); found && !strings.Contains(previous, IgnoreNextLinePragma) {
+					findings = append(findings, finding)
+				}
+			}
+			lineNumber++
+			previous = line[1:]
+		case strings.HasPrefix(line, " "):
+			lineNumber++
+			previous = line[1:]
+		}
+	}
+
+	return findings, lines.Err()
+}
+
+// ScanTree walks the tree from root on one goroutine while the workers read the files, returning every finding sorted by path and line
+// Binary files, .git, node_modules, vendor and the paths in the ignore file are skipped. Files that can't be read are reported in
+// the error and the scan carries on
+func (scanner *Scanner) ScanTree(root string) ([]Finding, error) {
+	ignore := scanner.options.Ignore
+	if ignore == nil {
+		var err error
+		if ignore, err = LoadIgnore(root); err != nil {
+			return nil, err
+		}
+	}
+
+	paths := make(chan string, 256)
+	results := make(chan []Finding, 256)
+
+	var errorsLock sync.Mutex
+	var readErrors []error
+	readError := func(path string, err error) {
+		errorsLock.Lock()
+		defer errorsLock.Unlock()
+		readErrors = append(readErrors, fmt.Errorf("unable to read %s: %w", path, err))
+	}
+
+	var walkErr error
+	go func() {
+		defer close(paths)
+
+		walkErr = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				// Like a file that can't be read, a folder that can't be listed is reported and the rest of the tree is still scanned
+				readError(path, err)
+				if d != nil && d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			// The ignore file's patterns are relative to the root
+			relative, _ := filepath.Rel(root, path)
+
+			if d.IsDir() {
+				if path != root && (utils.IsSkippedDirectory(d.Name()) || ignore.Match(relative, true)) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			if !d.Type().IsRegular() || !wantedFile(path) || ignore.Match(relative, false) {
+				return nil
+			}
+
+			if scanner.options.Lines != nil && !scanner.options.Lines.HasFile(path) {
+				return nil
+			}
+
+			paths <- path
+			return nil
+		})
+	}()
+
+	var workers sync.WaitGroup
+	for range scanner.options.Workers {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+
+			for path := range paths {
+				contents, err := os.ReadFile(path)
+				if err != nil {
+					readError(path, err)
+					continue
+				}
+
+				if utils.IsBinary(contents) {
+					continue
+				}
+
+				if found := scanner.scanContents(path, contents); len(found) > 0 {
+					results <- found
+				}
+			}
+		}()
+	}
+
+	go func() {
+		workers.Wait()
+		close(results)
+	}()
+
+	var findings []Finding
+	for found := range results {
+		findings = append(findings, found...)
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Path != findings[j].Path {
+			return findings[i].Path < findings[j].Path
+		}
+		return findings[i].Line < findings[j].Line
+	})
+
+	// The walk has finished by the time results is closed
+	return findings, errors.Join(append(readErrors, walkErr)...)
+}
+
+// Wanted is false for the files ScanTree would skip, by their path alone, for when the files are read some other way
+func Wanted(path string) bool {
+	directories := strings.Split(filepath.ToSlash(filepath.Dir(path)), "/")
+	if slices.ContainsFunc(directories, utils.IsSkippedDirectory) {
+		return false
+	}
+	return wantedFile(path)
+}
+
+func wantedFile(path string) bool {
+	name := filepath.Base(path)
+
+	// Files without an extension are skipped along with the known unwanted ones
+	if !strings.Contains(name, ".") || slices.Contains(unwantedFiles, name) {
+		return false
+	}
+
+	for _, extension := range unwantedExtentions {
+		if strings.Contains(name, extension) {
+			return false
+		}
+	}
+
+	return true
+}
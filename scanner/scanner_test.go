@@ -0,0 +1,236 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestScanReader(t *testing.T) {
+	contents := "package main\n\n// TODO: first thing\n\tx := 1 // (#12) TODO: second thing\n// not a todo\nfmt.Println(\"FIXME: third\")\n"
+
+	findings, err := New(Options{}).ScanReader("main.go", strings.NewReader(contents))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(findings) != 2 {
+		t.Fatalf("got %d findings, want 2: %+v", len(findings), findings)
+	}
+
+	first, second := findings[0], findings[1]
+	if first.Line != 3 || first.Column != 4 || first.Keyword != "TODO" || first.Text != "first thing" || first.IssueNumber != 0 {
+		t.Errorf("unexpected first finding %+v", first)
+	}
+	if second.Line != 4 || second.IssueNumber != 12 || second.Text != "second thing" || second.Source != "\tx := 1 // (#12) TODO: second thing" {
+		t.Errorf("unexpected second finding %+v", second)
+	}
+
+	findings, _ = New(Options{}).ScanReader("main.go", strings.NewReader("// TODO(@sam, due:2026-01-01): with an owner\n"))
+	if len(findings) != 1 || findings[0].Owner != "sam" || findings[0].Text != "with an owner" || findings[0].Due != "2026-01-01" {
+		t.Errorf("the owner or due date wasn't read: %+v", findings)
+	}
+
+	findings, _ = New(Options{}).ScanReader("main.go", strings.NewReader("// TODO(P1, due:2026-12-01, sam): prioritised\n// TODO(priority:p3): spelt out\n"))
+	if len(findings) != 2 || findings[0].Priority != "p1" || findings[0].Owner != "sam" || findings[0].Due != "2026-12-01" || findings[1].Priority != "p3" {
+		t.Errorf("the priority wasn't read: %+v", findings)
+	}
+
+	pragmas := "// thoth:ignore TODO: on the line\n// thoth:ignore-next-line\n// TODO: on the next line\n// TODO: still found\n"
+	findings, _ = New(Options{}).ScanReader("main.go", strings.NewReader(pragmas))
+	if len(findings) != 1 || findings[0].Line != 4 {
+		t.Errorf("the ignore pragmas weren't respected: %+v", findings)
+	}
+
+	findings, _ = New(Options{Keywords: []string{"TODO", "FIXME"}}).ScanReader("main.go", strings.NewReader(contents))
+	if len(findings) != 3 || findings[2].Keyword != "FIXME" {
+		t.Errorf("FIXME wasn't found with both keywords: %+v", findings)
+	}
+}
+
+// Lines only lets through the lines listed, like git.ChangedLines
+type lines map[string][]int
+
+func (filter lines) HasFile(path string) bool { return len(filter[filepath.ToSlash(path)]) > 0 }
+
+func (filter lines) Contains(path string, line int) bool {
+	for _, listed := range filter[filepath.ToSlash(path)] {
+		if listed == line {
+			return true
+		}
+	}
+	return false
+}
+
+func TestScanTree(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"main.go":                "// TODO: in the root\n",
+		"cmd/cmd.go":             "package cmd\n// TODO: in a folder\n// TODO: also in a folder\n",
+		"node_modules/left/i.js": "// TODO: skipped folder\n",
+		"README.md":              "TODO: skipped extension\n",
+		"Makefile":               "# TODO: no extension\n",
+		"image.png":              "\x00TODO: binary\n",
+		"cmd/nothing_to_see.go":  "package cmd\n",
+		"docs/guide.go":          "// TODO: ignored folder\n",
+		"cmd/cmd_test.go":        "// TODO: ignored file\n",
+		IgnoreFileName:           "docs/\n*_test.go\n",
+	}
+	for path, contents := range files {
+		full := filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	findings, err := New(Options{Workers: 3}).ScanTree(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, finding := range findings {
+		relative, _ := filepath.Rel(root, finding.Path)
+		got = append(got, filepath.ToSlash(relative)+":"+finding.Text)
+	}
+	want := []string{"cmd/cmd.go:in a folder", "cmd/cmd.go:also in a folder", "main.go:in the root"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	cmdPath := filepath.ToSlash(filepath.Join(root, "cmd", "cmd.go"))
+	findings, _ = New(Options{Lines: lines{cmdPath: {3}}}).ScanTree(root)
+	if len(findings) != 1 || findings[0].Line != 3 {
+		t.Errorf("the line filter wasn't applied: %+v", findings)
+	}
+}
+
+func TestScanTreeCarriesOn(t *testing.T) {
+	root := t.TempDir()
+	for path, contents := range map[string]string{"a.go": "// TODO: readable\n", "locked/b.go": "// TODO: locked away\n"} {
+		full := filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	locked := filepath.Join(root, "locked")
+	if err := os.Chmod(locked, 0); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chmod(locked, 0755)
+	if _, err := os.ReadDir(locked); err == nil {
+		t.Skip("the folder can still be read, as it can by root")
+	}
+
+	findings, err := New(Options{}).ScanTree(root)
+	if err == nil || !strings.Contains(err.Error(), "locked") {
+		t.Errorf("the folder that couldn't be read wasn't reported: %v", err)
+	}
+	if len(findings) != 1 || findings[0].Text != "readable" {
+		t.Errorf("the rest of the tree wasn't scanned: %+v", findings)
+	}
+}
+
+func TestScanDiff(t *testing.T) {
+	diff := `diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -3,2 +3,3 @@ func main() {
+ 	keep()
+-	// TODO: removed
++	// TODO: added
++	// (#4) TODO: added with a number
+@@ -20,0 +21 @@
++	// TODO: later on
+diff --git a/gone.go b/gone.go
+--- a/gone.go
++++ /dev/null
+@@ -1 +0,0 @@
+-// TODO: in a deleted file
+`
+
+	findings, err := New(Options{}).ScanDiff(strings.NewReader(diff))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, finding := range findings {
+		got = append(got, fmt.Sprintf("%s:%d:%s", finding.Path, finding.Line, finding.Text))
+	}
+	want := []string{"main.go:4:added", "main.go:5:added with a number", "main.go:21:later on"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestRewriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	contents := "package main\n\n// TODO: one\n// (#3) TODO: two\n\tcall() // TODO: three\n"
+	if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	findings, err := New(Options{}).ScanReader(path, strings.NewReader(contents))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rewriter := NewRewriter()
+	for index, finding := range findings {
+		rewriter.Number(finding, 10+index)
+	}
+
+	numbered, err := rewriter.Apply()
+	if err != nil || numbered != 2 {
+		t.Fatalf("numbered %d lines with error %v, want 2", numbered, err)
+	}
+
+	rewritten, _ := os.ReadFile(path)
+	want := "package main\n\n// (#10) TODO: one\n// (#3) TODO: two\n\tcall() // (#12) TODO: three\n"
+	if string(rewritten) != want {
+		t.Errorf("got %q, want %q", rewritten, want)
+	}
+
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("the file mode changed to %v", info.Mode().Perm())
+	}
+
+	// A line that changed since the scan is left alone
+	rewriter.Number(Finding{Path: path, Line: 3, Column: 4, Source: "// TODO: one"}, 99)
+	if numbered, err := rewriter.Apply(); numbered != 0 || err == nil {
+		t.Errorf("a changed line was numbered (%d, %v)", numbered, err)
+	}
+
+	// An ignored TODO gets the pragma in front of the keyword and isn't found again
+	ignored := Finding{Path: path, Line: 3, Column: 10, Source: "// (#10) TODO: one"}
+	rewriter.Ignore(ignored)
+	if changed, err := rewriter.Apply(); changed != 1 || err != nil {
+		t.Fatalf("ignoring changed %d lines with error %v, want 1", changed, err)
+	}
+
+	rewritten, _ = os.ReadFile(path)
+	findings, _ = New(Options{}).ScanReader(path, strings.NewReader(string(rewritten)))
+	if !strings.Contains(string(rewritten), "// (#10) thoth:ignore TODO: one") || len(findings) != 2 {
+		t.Errorf("the ignore pragma wasn't written or respected: %q %+v", rewritten, findings)
+	}
+
+	// ApplyTo rewrites files kept elsewhere, here in memory
+	files := map[string]string{"staged.go": "package a\n// TODO: staged\n"}
+	read := func(path string) (string, error) { return files[path], nil }
+	write := func(path, contents string) error { files[path] = contents; return nil }
+
+	rewriter.Number(Finding{Path: "staged.go", Line: 2, Column: 4, Source: "// TODO: staged"}, 5)
+	if changed, err := rewriter.ApplyTo(read, write); changed != 1 || err != nil || files["staged.go"] != "package a\n// (#5) TODO: staged\n" {
+		t.Errorf("ApplyTo changed %d lines with error %v: %q", changed, err, files["staged.go"])
+	}
+}